@@ -3,9 +3,14 @@ package cmd
 import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
+	jiraAssign "github.com/stirboy/jh/pkg/cmd/jira/assign"
 	"github.com/stirboy/jh/pkg/cmd/jira/auth"
+	jiraComment "github.com/stirboy/jh/pkg/cmd/jira/comment"
+	jiraConfig "github.com/stirboy/jh/pkg/cmd/jira/config"
 	jiraCreate "github.com/stirboy/jh/pkg/cmd/jira/create"
 	jiraGet "github.com/stirboy/jh/pkg/cmd/jira/get"
+	jiraList "github.com/stirboy/jh/pkg/cmd/jira/list"
+	jiraMove "github.com/stirboy/jh/pkg/cmd/jira/move"
 	"github.com/stirboy/jh/pkg/factory"
 )
 
@@ -31,6 +36,11 @@ func NewCmdRoot(f *factory.Factory) *cobra.Command {
 	cmd.AddCommand(auth.NewAuthCmd(f))
 	cmd.AddCommand(jiraCreate.NewCreateCmd(f))
 	cmd.AddCommand(jiraGet.NewGetCmd(f))
+	cmd.AddCommand(jiraList.NewListCmd(f))
+	cmd.AddCommand(jiraMove.NewMoveCmd(f))
+	cmd.AddCommand(jiraComment.NewCommentCmd(f))
+	cmd.AddCommand(jiraAssign.NewAssignCmd(f))
+	cmd.AddCommand(jiraConfig.NewConfigCmd(f))
 
 	auth.DisableAuthCheck(cmd)
 
@@ -0,0 +1,51 @@
+package adf
+
+// Node is a single node of Atlassian Document Format.
+// See https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+type Node struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*Node                `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*Mark                `json:"marks,omitempty"`
+}
+
+// Mark describes text formatting, e.g. strong, em or link
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// MentionResolver returns account id of a user mentioned as @name.
+// The second value reports whether the user was found.
+type MentionResolver func(name string) (string, bool)
+
+// NewDocument returns root node of a document
+func NewDocument(content ...*Node) *Node {
+	return &Node{
+		Type:    "doc",
+		Version: 1,
+		Content: content,
+	}
+}
+
+func textNode(text string, marks []*Mark) *Node {
+	return &Node{
+		Type:  "text",
+		Text:  text,
+		Marks: marks,
+	}
+}
+
+func sameMarks(a, b []*Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Attrs["href"] != b[i].Attrs["href"] {
+			return false
+		}
+	}
+	return true
+}
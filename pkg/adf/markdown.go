@@ -0,0 +1,396 @@
+package adf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+	ruleRe      = regexp.MustCompile(`^((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	listItemRe  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mentionRe   = regexp.MustCompile(`^@([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	mentionIDRe = regexp.MustCompile(`^@\[([A-Za-z0-9:_-]+)\]`)
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// EditorHint is appended to markdown edited in editor, StripComments removes it
+const EditorHint = `
+<!--
+Write in markdown. Supported are # headings, - lists,
+1. ordered lists, ` + "```" + `code blocks` + "```" + `, [links](https://example.com) and @mentions
+by name or @[account id].
+Comments like this one are ignored.
+-->
+`
+
+// StripComments removes html comments, which are used for hints in editor templates
+func StripComments(md string) string {
+	return strings.TrimSpace(htmlComment.ReplaceAllString(md, ""))
+}
+
+// FromMarkdown converts markdown to Atlassian Document Format.
+// Supported are headings, paragraphs, bullet and ordered lists (nested too),
+// fenced code blocks, block quotes, rules, links, inline code, emphasis and
+// @mentions. Mentions written as @[account id] are converted as they are, other
+// mentions only when resolve finds the user, otherwise they are left as plain text.
+// resolve can be nil.
+func FromMarkdown(md string, resolve MentionResolver) *Node {
+	p := &parser{resolve: resolve}
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	return NewDocument(p.parseBlocks(lines)...)
+}
+
+type parser struct {
+	resolve MentionResolver
+}
+
+func (p *parser) parseBlocks(lines []string) []*Node {
+	var nodes []*Node
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			i++
+		case isFence(trimmed):
+			var node *Node
+			node, i = p.parseCodeBlock(lines, i)
+			nodes = append(nodes, node)
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			nodes = append(nodes, &Node{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: p.parseInline(m[2], nil),
+			})
+			i++
+		case ruleRe.MatchString(trimmed):
+			nodes = append(nodes, &Node{Type: "rule"})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(l, " "))
+			}
+			nodes = append(nodes, &Node{
+				Type:    "blockquote",
+				Content: p.parseBlocks(quoted),
+			})
+		case listItemRe.MatchString(lines[i]):
+			var node *Node
+			node, i = p.parseList(lines, i)
+			nodes = append(nodes, node)
+		default:
+			var node *Node
+			node, i = p.parseParagraph(lines, i)
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
+
+func isBlockStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || isFence(trimmed) || headingRe.MatchString(trimmed) ||
+		ruleRe.MatchString(trimmed) || strings.HasPrefix(trimmed, ">") || listItemRe.MatchString(line)
+}
+
+func (p *parser) parseCodeBlock(lines []string, i int) (*Node, int) {
+	opening := strings.TrimSpace(lines[i])
+	fence := opening[:3]
+	language := strings.TrimSpace(strings.TrimLeft(opening, fence[:1]))
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+
+	node := &Node{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []*Node{textNode(text, nil)}
+	}
+
+	return node, i
+}
+
+func (p *parser) parseParagraph(lines []string, i int) (*Node, int) {
+	var text []string
+	for ; i < len(lines); i++ {
+		if len(text) > 0 && isBlockStart(lines[i]) {
+			break
+		}
+		text = append(text, strings.TrimSpace(lines[i]))
+	}
+
+	return &Node{
+		Type:    "paragraph",
+		Content: p.parseInline(strings.Join(text, " "), nil),
+	}, i
+}
+
+func (p *parser) parseList(lines []string, i int) (*Node, int) {
+	first := listItemRe.FindStringSubmatch(lines[i])
+	indent := len(first[1])
+	ordered := isOrderedMarker(first[2])
+
+	list := &Node{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+		if start, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); start > 1 {
+			list.Attrs = map[string]interface{}{"order": start}
+		}
+	}
+
+	var item []string
+	contentIndent := 0
+	flush := func() {
+		if item != nil {
+			list.Content = append(list.Content, &Node{
+				Type:    "listItem",
+				Content: p.parseBlocks(item),
+			})
+		}
+		item = nil
+	}
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			if !continuesList(lines, i, indent) {
+				break
+			}
+			item = append(item, "")
+			continue
+		}
+
+		m := listItemRe.FindStringSubmatch(line)
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		switch {
+		case m != nil && len(m[1]) == indent && isOrderedMarker(m[2]) == ordered:
+			flush()
+			item = []string{m[3]}
+			contentIndent = indent + len(m[2]) + 1
+		case m != nil && lineIndent < indent:
+			flush()
+			return list, i
+		case lineIndent > indent:
+			strip := lineIndent
+			if strip > contentIndent {
+				strip = contentIndent
+			}
+			item = append(item, line[strip:])
+		case !isBlockStart(line):
+			// lazy continuation of item paragraph
+			item = append(item, strings.TrimSpace(line))
+		default:
+			flush()
+			return list, i
+		}
+	}
+
+	flush()
+	return list, i
+}
+
+// continuesList reports whether list started at indent goes on after blank line i
+func continuesList(lines []string, i, indent int) bool {
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+		lineIndent := len(lines[j]) - len(strings.TrimLeft(lines[j], " \t"))
+		if lineIndent > indent {
+			return true
+		}
+		m := listItemRe.FindStringSubmatch(lines[j])
+		return m != nil && len(m[1]) == indent
+	}
+	return false
+}
+
+func isOrderedMarker(marker string) bool {
+	return marker[0] >= '0' && marker[0] <= '9'
+}
+
+func (p *parser) parseInline(s string, marks []*Mark) []*Node {
+	var nodes []*Node
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = appendText(nodes, buf.String(), marks)
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.ContainsRune("\\`*_{}[]()#+-.!~@<>", rune(s[i+1])):
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				flush()
+				nodes = append(nodes, textNode(rest[1:1+end], codeMarks(marks)))
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			delim := rest[:2]
+			if end := strings.Index(rest[2:], delim); end > 0 && canOpen(s, i, len(delim)) {
+				mark := "strong"
+				if delim == "~~" {
+					mark = "strike"
+				}
+				flush()
+				nodes = append(nodes, p.parseInline(rest[2:2+end], withMark(marks, &Mark{Type: mark}))...)
+				i += end + 4
+				continue
+			}
+		case c == '*' || c == '_':
+			if end := strings.IndexByte(rest[1:], c); end > 0 && canOpen(s, i, 1) && canClose(s, i+1+end) {
+				flush()
+				nodes = append(nodes, p.parseInline(rest[1:1+end], withMark(marks, &Mark{Type: "em"}))...)
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if text, href, n := parseLink(rest); n > 0 {
+				flush()
+				link := &Mark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+				nodes = append(nodes, p.parseInline(text, withMark(marks, link))...)
+				i += n
+				continue
+			}
+		case c == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && isURL(rest[1:end]) {
+				flush()
+				href := rest[1:end]
+				link := &Mark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+				nodes = appendText(nodes, href, withMark(marks, link))
+				i += end + 1
+				continue
+			}
+		case c == '@' && (i == 0 || !isWordChar(s[i-1])) && mentionIDRe.MatchString(rest):
+			m := mentionIDRe.FindStringSubmatch(rest)
+			flush()
+			nodes = append(nodes, &Node{
+				Type:  "mention",
+				Attrs: map[string]interface{}{"id": m[1]},
+			})
+			i += len(m[0])
+			continue
+		case c == '@' && p.resolve != nil && (i == 0 || !isWordChar(s[i-1])):
+			if m := mentionRe.FindStringSubmatch(rest); m != nil {
+				name := strings.TrimRight(m[1], ".")
+				if id, ok := p.resolve(name); ok {
+					flush()
+					nodes = append(nodes, &Node{
+						Type:  "mention",
+						Attrs: map[string]interface{}{"id": id, "text": "@" + name},
+					})
+					i += len(name) + 1
+					continue
+				}
+			}
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+	flush()
+
+	return nodes
+}
+
+// parseLink parses [text](href) at the start of s,
+// returns number of consumed bytes or 0 if s does not start with a link
+func parseLink(s string) (string, string, int) {
+	closing := strings.Index(s, "](")
+	if closing < 0 {
+		return "", "", 0
+	}
+	end := strings.IndexByte(s[closing:], ')')
+	if end < 0 {
+		return "", "", 0
+	}
+	end += closing
+
+	href := strings.TrimSpace(s[closing+2 : end])
+	if href == "" || strings.ContainsAny(href, " \t") {
+		return "", "", 0
+	}
+
+	return s[1:closing], href, end + 1
+}
+
+func isURL(s string) bool {
+	return (strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")) && !strings.ContainsAny(s, " \t")
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// canOpen reports whether delimiter at i can open emphasis:
+// it must be followed by non space and, for snake_case safety, not preceded by a word char
+func canOpen(s string, i, n int) bool {
+	if i+n >= len(s) || s[i+n] == ' ' {
+		return false
+	}
+	return s[i] == '*' || s[i] == '~' || i == 0 || !isWordChar(s[i-1])
+}
+
+// canClose reports whether delimiter at i can close emphasis
+func canClose(s string, i int) bool {
+	if s[i-1] == ' ' {
+		return false
+	}
+	return s[i] == '*' || i+1 >= len(s) || !isWordChar(s[i+1])
+}
+
+func withMark(marks []*Mark, mark *Mark) []*Mark {
+	result := make([]*Mark, 0, len(marks)+1)
+	result = append(result, marks...)
+	return append(result, mark)
+}
+
+// codeMarks returns marks for inline code, which can be combined with links only
+func codeMarks(marks []*Mark) []*Mark {
+	var result []*Mark
+	for _, m := range marks {
+		if m.Type == "link" {
+			result = append(result, m)
+		}
+	}
+	return append(result, &Mark{Type: "code"})
+}
+
+func appendText(nodes []*Node, text string, marks []*Mark) []*Node {
+	if len(nodes) > 0 {
+		last := nodes[len(nodes)-1]
+		if last.Type == "text" && sameMarks(last.Marks, marks) {
+			last.Text += text
+			return nodes
+		}
+	}
+	return append(nodes, textNode(text, marks))
+}
@@ -0,0 +1,122 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMarkdown(t *testing.T) {
+	resolve := func(name string) (string, bool) {
+		if name == "john" {
+			return "acc-1", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "empty",
+			markdown: "",
+			want:     `{"type":"doc","version":1}`,
+		},
+		{
+			name:     "paragraphs",
+			markdown: "first\nline\n\nsecond",
+			want: `{"type":"doc","version":1,"content":[
+				{"type":"paragraph","content":[{"type":"text","text":"first line"}]},
+				{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}`,
+		},
+		{
+			name:     "heading",
+			markdown: "## Steps ##",
+			want: `{"type":"doc","version":1,"content":[
+				{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]}]}`,
+		},
+		{
+			name:     "inline marks",
+			markdown: "**bold** *em* ~~gone~~ `code` snake_case_name",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[
+				{"type":"text","text":"bold","marks":[{"type":"strong"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"em","marks":[{"type":"em"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"gone","marks":[{"type":"strike"}]},
+				{"type":"text","text":" "},
+				{"type":"text","text":"code","marks":[{"type":"code"}]},
+				{"type":"text","text":" snake_case_name"}]}]}`,
+		},
+		{
+			name:     "links",
+			markdown: "see [docs](https://example.com) or <https://jira.com>",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[
+				{"type":"text","text":"see "},
+				{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+				{"type":"text","text":" or "},
+				{"type":"text","text":"https://jira.com","marks":[{"type":"link","attrs":{"href":"https://jira.com"}}]}]}]}`,
+		},
+		{
+			name:     "mentions",
+			markdown: "ping @john and @unknown, mail me@example.com",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[
+				{"type":"text","text":"ping "},
+				{"type":"mention","attrs":{"id":"acc-1","text":"@john"}},
+				{"type":"text","text":" and @unknown, mail me@example.com"}]}]}`,
+		},
+		{
+			name:     "mentions by account id",
+			markdown: "ping @[557058:f58131cb-b67d] and @[acc-2]",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[
+				{"type":"text","text":"ping "},
+				{"type":"mention","attrs":{"id":"557058:f58131cb-b67d"}},
+				{"type":"text","text":" and "},
+				{"type":"mention","attrs":{"id":"acc-2"}}]}]}`,
+		},
+		{
+			name:     "code block",
+			markdown: "```go\nfunc main() {\n\n}\n```",
+			want: `{"type":"doc","version":1,"content":[
+				{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"func main() {\n\n}"}]}]}`,
+		},
+		{
+			name:     "nested lists",
+			markdown: "- one\n  1. sub\n  2. sub2\n- two\n\n3. three",
+			want: `{"type":"doc","version":1,"content":[
+				{"type":"bulletList","content":[
+					{"type":"listItem","content":[
+						{"type":"paragraph","content":[{"type":"text","text":"one"}]},
+						{"type":"orderedList","content":[
+							{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"sub"}]}]},
+							{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"sub2"}]}]}]}]},
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"two"}]}]}]},
+				{"type":"orderedList","attrs":{"order":3},"content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"three"}]}]}]}]}`,
+		},
+		{
+			name:     "quote and rule",
+			markdown: "> quoted\n> text\n\n---",
+			want: `{"type":"doc","version":1,"content":[
+				{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted text"}]}]},
+				{"type":"rule"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(FromMarkdown(tt.markdown, resolve))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	got := StripComments("<!-- hint\nmultiline -->\nbody <!-- inline -->\n")
+	assert.Equal(t, "body", got)
+}
@@ -0,0 +1,258 @@
+package adf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToMarkdown converts Atlassian Document Format to markdown understood by FromMarkdown.
+// Mentions are written as @[account id]. Nodes markdown can't express, e.g. tables
+// or panels, are rendered by their content, see Unsupported.
+func ToMarkdown(doc *Node) string {
+	if doc == nil {
+		return ""
+	}
+
+	return strings.Join(markdownBlocks(doc.Content, "\n\n"), "\n\n")
+}
+
+func markdownBlocks(nodes []*Node, sep string) []string {
+	var blocks []string
+	for _, n := range nodes {
+		if b := markdownBlock(n, sep); b != "" {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func markdownBlock(n *Node, sep string) string {
+	switch n.Type {
+	case "paragraph":
+		return markdownInline(n.Content)
+	case "heading":
+		level := intAttr(n, "level", 1)
+		return strings.Repeat("#", level) + " " + markdownInline(n.Content)
+	case "rule":
+		return "---"
+	case "codeBlock":
+		language, _ := n.Attrs["language"].(string)
+		return "```" + language + "\n" + PlainText(n) + "\n```"
+	case "blockquote":
+		return prefixLines(strings.Join(markdownBlocks(n.Content, sep), "\n\n"), "> ", "> ")
+	case "bulletList", "orderedList":
+		return markdownList(n)
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "date", "status":
+		return markdownInline([]*Node{n})
+	}
+
+	if len(n.Content) > 0 && isInline(n.Content[0]) {
+		return markdownInline(n.Content)
+	}
+	return strings.Join(markdownBlocks(n.Content, sep), sep)
+}
+
+func markdownList(n *Node) string {
+	start := intAttr(n, "order", 1)
+
+	items := make([]string, 0, len(n.Content))
+	for i, item := range n.Content {
+		marker := "- "
+		if n.Type == "orderedList" {
+			marker = strconv.Itoa(start+i) + ". "
+		}
+		// list items are kept tight, so that nested lists stay in the item
+		body := strings.Join(markdownBlocks(item.Content, "\n"), "\n")
+		items = append(items, prefixLines(body, marker, strings.Repeat(" ", len(marker))))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func markdownInline(nodes []*Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(markText(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			// account id is kept, so that FromMarkdown finds the same user
+			if id := stringAttr(n, "id"); id != "" {
+				b.WriteString("@[" + id + "]")
+			} else {
+				b.WriteString(inlineText(n))
+			}
+		default:
+			b.WriteString(inlineText(n))
+		}
+	}
+	return b.String()
+}
+
+func markText(text string, marks []*Mark) string {
+	var link string
+	for _, m := range marks {
+		if m.Type == "code" {
+			text = "`" + text + "`"
+		}
+	}
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			link, _ = m.Attrs["href"].(string)
+		}
+	}
+	if link != "" {
+		text = "[" + text + "](" + link + ")"
+	}
+	return text
+}
+
+// inlineText returns text of inline nodes which are not plain text
+func inlineText(n *Node) string {
+	switch n.Type {
+	case "mention":
+		text, _ := n.Attrs["text"].(string)
+		if text == "" {
+			return ""
+		}
+		return "@" + strings.TrimPrefix(text, "@")
+	case "emoji":
+		if text, _ := n.Attrs["text"].(string); text != "" {
+			return text
+		}
+		shortName, _ := n.Attrs["shortName"].(string)
+		return shortName
+	case "inlineCard":
+		url, _ := n.Attrs["url"].(string)
+		return url
+	case "date":
+		ms, err := strconv.ParseInt(stringAttr(n, "timestamp"), 10, 64)
+		if err != nil {
+			return ""
+		}
+		return time.UnixMilli(ms).UTC().Format("2006-01-02")
+	case "status":
+		return stringAttr(n, "text")
+	case "text":
+		return n.Text
+	}
+
+	return markdownInline(n.Content)
+}
+
+// markdownNodes and markdownMarks are converted by ToMarkdown and back by FromMarkdown
+var (
+	markdownNodes = map[string]bool{
+		"doc": true, "paragraph": true, "heading": true, "rule": true, "codeBlock": true,
+		"blockquote": true, "bulletList": true, "orderedList": true, "listItem": true,
+		"text": true, "hardBreak": true, "mention": true, "emoji": true, "inlineCard": true,
+	}
+	markdownMarks = map[string]bool{"code": true, "strong": true, "em": true, "strike": true, "link": true}
+)
+
+// Unsupported returns sorted types of nodes and marks of doc, which markdown can't
+// express, e.g. table, panel or media. They are lost when doc is edited as markdown.
+func Unsupported(doc *Node) []string {
+	found := map[string]bool{}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !markdownNodes[n.Type] {
+			found[n.Type] = true
+		}
+		for _, m := range n.Marks {
+			if !markdownMarks[m.Type] {
+				found[m.Type] = true
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	if doc != nil {
+		walk(doc)
+	}
+
+	types := make([]string, 0, len(found))
+	for t := range found {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// PlainText returns text of node and its children without any formatting
+func PlainText(n *Node) string {
+	if n == nil {
+		return ""
+	}
+	if n.Type == "text" {
+		return n.Text
+	}
+	if n.Type == "hardBreak" {
+		return "\n"
+	}
+	if len(n.Content) == 0 {
+		return inlineText(n)
+	}
+
+	var b strings.Builder
+	for _, c := range n.Content {
+		b.WriteString(PlainText(c))
+	}
+	return b.String()
+}
+
+func isInline(n *Node) bool {
+	switch n.Type {
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "date", "status":
+		return true
+	}
+	return false
+}
+
+// prefixLines prefixes the first line of s with first and the others with rest
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if l == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func intAttr(n *Node, name string, def int) int {
+	switch v := n.Attrs[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return def
+}
+
+func stringAttr(n *Node, name string) string {
+	switch v := n.Attrs[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "empty",
+			doc:  `{"type":"doc","version":1}`,
+			want: "",
+		},
+		{
+			name: "blocks",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]},
+				{"type":"paragraph","content":[
+					{"type":"text","text":"open "},
+					{"type":"text","text":"app","marks":[{"type":"strong"}]},
+					{"type":"text","text":" see "},
+					{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]},
+				{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1\ny := 2"}]},
+				{"type":"rule"},
+				{"type":"blockquote","content":[
+					{"type":"paragraph","content":[{"type":"text","text":"quoted"}]},
+					{"type":"paragraph","content":[{"type":"text","text":"twice"}]}]}]}`,
+			want: "## Steps\n\nopen **app** see [docs](https://example.com)\n\n```go\nx := 1\ny := 2\n```\n\n---\n\n> quoted\n>\n> twice",
+		},
+		{
+			name: "nested lists",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"orderedList","attrs":{"order":3},"content":[
+					{"type":"listItem","content":[
+						{"type":"paragraph","content":[{"type":"text","text":"first"}]},
+						{"type":"bulletList","content":[
+							{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"inner"}]}]}]}]},
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}]}]}`,
+			want: "3. first\n   - inner\n4. second",
+		},
+		{
+			name: "inline nodes",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"paragraph","content":[
+					{"type":"mention","attrs":{"id":"acc-1","text":"@John Doe"}},
+					{"type":"text","text":" "},
+					{"type":"emoji","attrs":{"shortName":":smile:"}},
+					{"type":"text","text":" "},
+					{"type":"inlineCard","attrs":{"url":"https://example.com/x"}},
+					{"type":"hardBreak"},
+					{"type":"text","text":"run","marks":[{"type":"code"}]},
+					{"type":"text","text":" by "},
+					{"type":"date","attrs":{"timestamp":"1700000000000"}}]},
+				{"type":"panel","attrs":{"panelType":"info"},"content":[
+					{"type":"paragraph","content":[{"type":"text","text":"note"}]}]}]}`,
+			want: "@[acc-1] :smile: https://example.com/x\n`run` by 2023-11-14\n\nnote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Node{}
+			require.NoError(t, json.Unmarshal([]byte(tt.doc), doc))
+
+			assert.Equal(t, tt.want, ToMarkdown(doc))
+		})
+	}
+}
+
+func TestToMarkdown_roundTrip(t *testing.T) {
+	md := "# Title\n\nsome **bold** and _em_ text with `code` for @[acc-1]\n\n- one\n- two\n  1. nested\n\n> quote"
+
+	assert.Equal(t, md, ToMarkdown(FromMarkdown(md, nil)))
+}
+
+func TestUnsupported(t *testing.T) {
+	doc := &Node{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"doc","version":1,"content":[
+		{"type":"paragraph","content":[
+			{"type":"text","text":"see","marks":[{"type":"strong"},{"type":"underline"}]},
+			{"type":"mention","attrs":{"id":"acc-1"}}]},
+		{"type":"panel","attrs":{"panelType":"info"},"content":[
+			{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableCell","content":[]}]}]}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"1","type":"file"}}]}]}`), doc))
+
+	assert.Equal(t, []string{"media", "mediaSingle", "panel", "table", "tableCell", "tableRow", "underline"}, Unsupported(doc))
+	assert.Empty(t, Unsupported(FromMarkdown("# Title\n\n- **one** @[acc-1]", nil)))
+}
@@ -0,0 +1,141 @@
+package adf
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+// ToTerminal renders Atlassian Document Format as text styled for terminal.
+// Unlike ToMarkdown, formatting is shown by color scheme instead of markup.
+func ToTerminal(doc *Node, cs *iostreams.ColorScheme) string {
+	if doc == nil {
+		return ""
+	}
+
+	r := &terminalRenderer{cs: cs}
+	return strings.Join(r.blocks(doc.Content, "\n\n"), "\n\n")
+}
+
+type terminalRenderer struct {
+	cs *iostreams.ColorScheme
+}
+
+func (r *terminalRenderer) blocks(nodes []*Node, sep string) []string {
+	var blocks []string
+	for _, n := range nodes {
+		if b := r.block(n, sep); b != "" {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func (r *terminalRenderer) block(n *Node, sep string) string {
+	switch n.Type {
+	case "paragraph":
+		return r.inline(n.Content)
+	case "heading":
+		return r.cs.Bold(PlainText(n))
+	case "rule":
+		return r.cs.Gray(strings.Repeat("─", 20))
+	case "codeBlock":
+		return r.cs.Cyan(prefixLines(PlainText(n), "    ", "    "))
+	case "blockquote":
+		return prefixLines(strings.Join(r.blocks(n.Content, sep), "\n\n"), r.cs.Gray("│ "), r.cs.Gray("│ "))
+	case "bulletList", "orderedList":
+		return r.list(n)
+	case "table":
+		return r.table(n)
+	case "mediaSingle", "mediaGroup", "media":
+		return r.cs.Gray("[attachment]")
+	}
+
+	if len(n.Content) > 0 && isInline(n.Content[0]) {
+		return r.inline(n.Content)
+	}
+	return strings.Join(r.blocks(n.Content, sep), sep)
+}
+
+func (r *terminalRenderer) list(n *Node) string {
+	start := intAttr(n, "order", 1)
+
+	items := make([]string, 0, len(n.Content))
+	for i, item := range n.Content {
+		marker := "• "
+		if n.Type == "orderedList" {
+			marker = strconv.Itoa(start+i) + ". "
+		}
+		body := strings.Join(r.blocks(item.Content, "\n"), "\n")
+		indent := strings.Repeat(" ", len([]rune(marker)))
+		items = append(items, prefixLines(body, marker, indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// table renders every row on its own line, cells are separated by a bar
+func (r *terminalRenderer) table(n *Node) string {
+	rows := make([]string, 0, len(n.Content))
+	for _, row := range n.Content {
+		cells := make([]string, 0, len(row.Content))
+		for _, cell := range row.Content {
+			text := strings.ReplaceAll(PlainText(cell), "\n", " ")
+			if cell.Type == "tableHeader" {
+				text = r.cs.Bold(text)
+			}
+			cells = append(cells, text)
+		}
+		rows = append(rows, strings.Join(cells, r.cs.Gray(" │ ")))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (r *terminalRenderer) inline(nodes []*Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(r.text(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(r.cs.Blue(inlineText(n)))
+		case "inlineCard":
+			b.WriteString(r.cs.Underline(inlineText(n)))
+		case "status":
+			b.WriteString(r.cs.Bold(strings.ToUpper(inlineText(n))))
+		default:
+			b.WriteString(inlineText(n))
+		}
+	}
+	return b.String()
+}
+
+func (r *terminalRenderer) text(text string, marks []*Mark) string {
+	var link string
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = r.cs.Bold(text)
+		case "em":
+			text = r.cs.Italic(text)
+		case "strike":
+			text = r.cs.Strike(text)
+		case "underline":
+			text = r.cs.Underline(text)
+		case "code":
+			text = r.cs.Cyan(text)
+		case "link":
+			link, _ = m.Attrs["href"].(string)
+		}
+	}
+	if link == "" {
+		return text
+	}
+	if strings.Contains(text, link) {
+		return r.cs.Underline(text)
+	}
+	return r.cs.Underline(text) + " " + r.cs.Gray("("+link+")")
+}
@@ -0,0 +1,56 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTerminal(t *testing.T) {
+	doc := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Steps"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"open "},
+			{"type":"text","text":"app","marks":[{"type":"strong"}]},
+			{"type":"text","text":" see "},
+			{"type":"text","text":"docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},
+			{"type":"text","text":" by "},
+			{"type":"mention","attrs":{"id":"acc-1","text":"@John"}}]},
+		{"type":"codeBlock","content":[{"type":"text","text":"x := 1\ny := 2"}]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"orderedList","content":[
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"first"}]},
+				{"type":"bulletList","content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"inner"}]}]}]}]}]},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"2"}]}]}]}]},
+		{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"1"}}]}]}`
+
+	n := &Node{}
+	require.NoError(t, json.Unmarshal([]byte(doc), n))
+
+	t.Run("plain", func(t *testing.T) {
+		assert.Equal(t,
+			"Steps\n\nopen app see docs (https://example.com) by @John\n\n    x := 1\n    y := 2\n\n│ quoted\n\n1. first\n   • inner\n\na │ b\n1 │ 2\n\n[attachment]",
+			ToTerminal(n, iostreams.NewColorScheme(false)))
+	})
+
+	t.Run("styled", func(t *testing.T) {
+		out := ToTerminal(n, iostreams.NewColorScheme(true))
+		assert.Contains(t, out, "\033[1mSteps\033[0m")
+		assert.Contains(t, out, "open \033[1mapp\033[0m see \033[4mdocs\033[0m \033[90m(https://example.com)\033[0m by \033[34m@John\033[0m")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Equal(t, "", ToTerminal(nil, iostreams.NewColorScheme(true)))
+	})
+}
@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/google/shlex"
+)
+
+// Open opens url in $BROWSER or in the default browser of the system
+func Open(url string) error {
+	var args []string
+	if b := os.Getenv("BROWSER"); b != "" {
+		parts, err := shlex.Split(b)
+		if err != nil {
+			return err
+		}
+		args = append(parts, url)
+	} else {
+		switch runtime.GOOS {
+		case "darwin":
+			args = []string{"open", url}
+		case "windows":
+			args = []string{"cmd", "/c", "start", "", url}
+		default:
+			args = []string{"xdg-open", url}
+		}
+	}
+
+	// browser is not waited for, it keeps running after jh exits
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	return cmd.Start()
+}
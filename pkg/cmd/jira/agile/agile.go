@@ -0,0 +1,124 @@
+package agile
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// pageSize is a max number of values jira agile api returns per page
+const pageSize = 50
+
+type Board struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Sprint struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+type boardsPage struct {
+	Values []Board `json:"values"`
+	IsLast bool    `json:"isLast"`
+}
+
+type sprintsPage struct {
+	Values []Sprint `json:"values"`
+	IsLast bool     `json:"isLast"`
+}
+
+// GetScrumBoards returns scrum boards of the project, only they have sprints
+func GetScrumBoards(jiraClient *jira.Client, projectKey string) ([]Board, error) {
+	var boards []Board
+	for {
+		values := url.Values{}
+		values.Set("projectKeyOrId", projectKey)
+		values.Set("type", "scrum")
+		values.Set("startAt", strconv.Itoa(len(boards)))
+		values.Set("maxResults", strconv.Itoa(pageSize))
+
+		page := &boardsPage{}
+		if err := get(jiraClient, "rest/agile/1.0/board?"+values.Encode(), page); err != nil {
+			return nil, err
+		}
+
+		boards = append(boards, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return boards, nil
+		}
+	}
+}
+
+// GetSprints returns sprints of the board in given states (active, future or closed),
+// sprints are ordered as they are on the board
+func GetSprints(jiraClient *jira.Client, boardID int, states ...string) ([]Sprint, error) {
+	var sprints []Sprint
+	for {
+		values := url.Values{}
+		values.Set("state", strings.Join(states, ","))
+		values.Set("startAt", strconv.Itoa(len(sprints)))
+		values.Set("maxResults", strconv.Itoa(pageSize))
+
+		page := &sprintsPage{}
+		path := fmt.Sprintf("rest/agile/1.0/board/%d/sprint?%s", boardID, values.Encode())
+		if err := get(jiraClient, path, page); err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+	}
+}
+
+// MoveIssuesToSprint moves issues into the sprint, issues are removed from any other sprint
+func MoveIssuesToSprint(jiraClient *jira.Client, sprintID int, issueKeys ...string) error {
+	return post(jiraClient, fmt.Sprintf("rest/agile/1.0/sprint/%d/issue", sprintID), issueKeys)
+}
+
+// MoveIssuesToEpic makes the epic a parent of issues
+func MoveIssuesToEpic(jiraClient *jira.Client, epicKey string, issueKeys ...string) error {
+	return post(jiraClient, "rest/agile/1.0/epic/"+url.PathEscape(epicKey)+"/issue", issueKeys)
+}
+
+func get(jiraClient *jira.Client, path string, v interface{}) error {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, v)
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+
+	return nil
+}
+
+func post(jiraClient *jira.Client, path string, issueKeys []string) error {
+	body := struct {
+		Issues []string `json:"issues"`
+	}{issueKeys}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package assign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+const (
+	// unassigned removes assignee of issue
+	unassigned = "none"
+	// pickerLimit is a max number of users offered by picker
+	pickerLimit = 50
+)
+
+type AssignOptions struct {
+	JiraIssueKey string
+	// User is a name, email or account id of assignee, @me or none
+	User       string
+	JiraClient func() (*jira.Client, error)
+	IOStream   *iostreams.IOStream
+	Prompter   prompt.Prompter
+	// KeyResolver finds issue key in current branch, when it is not given
+	KeyResolver *issues.KeyResolver
+	// UserCache remembers users found by name or email
+	UserCache *users.Cache
+}
+
+func NewAssignCmd(f *factory.Factory) *cobra.Command {
+	ops := &AssignOptions{
+		JiraClient:  f.JiraClient,
+		IOStream:    f.IOStream,
+		Prompter:    f.Prompter,
+		KeyResolver: issues.NewKeyResolver(f),
+		UserCache:   users.NewCache(users.DefaultCachePath()),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "assign [<jira-key>] [<user>|@me|none]",
+		Short: "assign jira issue",
+		Long: heredoc.Doc(`
+			Assign jira issue to a user who can be assigned to it. User is searched by
+			name or email, when several users match, one of them is picked in terminal.
+			@me assigns issue to you, none unassigns it. Without user, assignee is picked
+			among all assignable users.
+
+			Users found by name or email are cached per project, see JH_CACHE_DIR.
+			Users picked among several matching users are not cached.
+			Without key, issue key is taken from current git branch.
+		`),
+		Example: heredoc.Doc(`
+			$ jh assign PROJ-1 jane
+			$ jh assign PROJ-1 jane.doe@example.com
+			$ jh assign PROJ-1 none
+
+			# assign issue of current branch to yourself
+			$ jh assign @me
+		`),
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) == 2:
+				ops.JiraIssueKey, ops.User = strings.ToUpper(args[0]), args[1]
+			case len(args) == 1 && issues.IsKey(args[0]):
+				ops.JiraIssueKey = strings.ToUpper(args[0])
+			case len(args) == 1:
+				ops.User = args[0]
+			}
+
+			if ops.User == "" && !ops.IOStream.CanPrompt() {
+				return errors.New("user is required when not running interactively")
+			}
+
+			if ops.JiraIssueKey == "" {
+				key, err := ops.KeyResolver.Resolve(nil)
+				if err != nil {
+					return err
+				}
+				ops.JiraIssueKey = key
+			}
+			return run(ops)
+		},
+	}
+
+	return cmd
+}
+
+func run(ops *AssignOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	var user *jira.User
+	switch {
+	case ops.User == "":
+		if user, err = pickAssignee(ops, jiraClient); err != nil {
+			return err
+		}
+	case !strings.EqualFold(ops.User, unassigned):
+		if user, err = findAssignee(ops, jiraClient); err != nil {
+			return err
+		}
+	}
+
+	if err = setAssignee(jiraClient, ops.JiraIssueKey, user); err != nil {
+		// cached user may be no longer assignable, it is looked up again next time
+		_ = ops.UserCache.Delete(cacheScope(jiraClient, ops.JiraIssueKey), ops.User)
+		return err
+	}
+
+	if user == nil {
+		fmt.Fprintf(ops.IOStream.Out, "unassigned %s\n", ops.JiraIssueKey)
+		return nil
+	}
+	fmt.Fprintf(ops.IOStream.Out, "assigned %s to %s\n", ops.JiraIssueKey, user.DisplayName)
+	return nil
+}
+
+// findAssignee resolves user given by name, email or @me to one user. Users found
+// unambiguously are cached, users picked among several matches are not.
+func findAssignee(ops *AssignOptions, jiraClient *jira.Client) (*jira.User, error) {
+	scope := cacheScope(jiraClient, ops.JiraIssueKey)
+	if u, ok := ops.UserCache.Get(scope, ops.User); ok {
+		return u, nil
+	}
+
+	var user *jira.User
+	if ops.User == users.Me {
+		u, resp, err := users.GetCurrentUser(jiraClient)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get current user: %w", jira.NewJiraError(resp, err))
+		}
+		user = u
+	} else {
+		found, err := users.SearchAssignableUsers(jiraClient, ops.JiraIssueKey, ops.User, pickerLimit)
+		if err != nil {
+			return nil, fmt.Errorf("cannot search users: %w", err)
+		}
+
+		switch {
+		case len(found) == 0:
+			return nil, fmt.Errorf("no user matching %q can be assigned to %s", ops.User, ops.JiraIssueKey)
+		case len(found) == 1:
+			user = &found[0]
+		case users.ExactMatch(found, ops.User) != nil:
+			user = users.ExactMatch(found, ops.User)
+		case !ops.IOStream.CanPrompt():
+			names := make([]string, 0, len(found))
+			for i := range found {
+				names = append(names, users.Label(&found[i]))
+			}
+			return nil, fmt.Errorf("%q matches several users: %s", ops.User, strings.Join(names, ", "))
+		default:
+			// picked user is not cached, next time query may mean someone else
+			return selectUser(ops.Prompter, fmt.Sprintf("%q matches several users, assign %s to", ops.User, ops.JiraIssueKey), found, false)
+		}
+	}
+
+	// failing cache doesn't prevent assignment
+	_ = ops.UserCache.Set(scope, ops.User, user)
+	return user, nil
+}
+
+// pickAssignee lets user pick one of all assignable users, nil means unassigned
+func pickAssignee(ops *AssignOptions, jiraClient *jira.Client) (*jira.User, error) {
+	found, err := users.SearchAssignableUsers(jiraClient, ops.JiraIssueKey, "", pickerLimit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search users: %w", err)
+	}
+	return selectUser(ops.Prompter, "Assign "+ops.JiraIssueKey+" to", found, true)
+}
+
+// selectUser lets user pick one of found users, with unassigned option nil can be picked
+func selectUser(prompter prompt.Prompter, message string, found []jira.User, withUnassigned bool) (*jira.User, error) {
+	options := make([]string, 0, len(found)+1)
+	for i := range found {
+		options = append(options, users.Label(&found[i]))
+	}
+	if withUnassigned {
+		options = append(options, "Unassigned")
+	}
+
+	choice, err := prompter.Select(message, options)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		if users.Label(&found[i]) == choice {
+			return &found[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// setAssignee assigns issue to user, nil user unassigns issue
+func setAssignee(jiraClient *jira.Client, key string, user *jira.User) error {
+	var accountID *string
+	if user != nil {
+		accountID = &user.AccountID
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPut,
+		"rest/api/3/issue/"+url.PathEscape(key)+"/assignee", map[string]*string{"accountId": accountID})
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return fmt.Errorf("cannot assign %s: %w", key, jira.NewJiraError(resp, err))
+	}
+	return nil
+}
+
+// cacheScope identifies jira instance and project users are cached for,
+// users assignable in one project may not be assignable in another
+func cacheScope(jiraClient *jira.Client, key string) string {
+	project := key
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		project = key[:i]
+	}
+	return jiraClient.BaseURL.String() + " " + project
+}
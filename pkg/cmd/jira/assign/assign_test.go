@@ -0,0 +1,288 @@
+package assign
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+const janesJSON = `[
+	{"accountId": "42", "displayName": "Jane Doe", "emailAddress": "jane@example.com"},
+	{"accountId": "43", "displayName": "Jane Roe", "emailAddress": "roe@example.com"}
+]`
+
+// bodyRecorder records json body of request and responds with no content
+func bodyRecorder(body *json.RawMessage) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(req.Body).Decode(body)
+		return httpmock.StatusStringResponse(204, "")(req)
+	}
+}
+
+func assignableSearch(key, query string) httpmock.Matcher {
+	return httpmock.QueryMatcher("GET", "rest/api/3/user/assignable/search", url.Values{
+		"issueKey": []string{key}, "query": []string{query},
+	})
+}
+
+func TestAssign(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		tty        bool
+		prompter   *prompt.PrompterMock
+		stub       func(r *httpmock.Registry, body *json.RawMessage)
+		expectBody string
+		expectOut  string
+		expectErr  string
+	}{
+		{
+			name: "should assign single matching user",
+			args: []string{"proj-1", "jane"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", "jane"),
+					httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": "42"}`,
+			expectOut:  "assigned PROJ-1 to Jane Doe\n",
+		},
+		{
+			name: "should assign user matching email exactly",
+			args: []string{"PROJ-1", "roe@example.com"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", "roe@example.com"), httpmock.StringResponse(janesJSON))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": "43"}`,
+			expectOut:  "assigned PROJ-1 to Jane Roe\n",
+		},
+		{
+			name: "should pick one of several matching users",
+			args: []string{"PROJ-1", "jane"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				SelectFunc: func(message string, options []string) (string, error) {
+					assert.Equal(t, `"jane" matches several users, assign PROJ-1 to`, message)
+					assert.Equal(t, []string{"Jane Doe <jane@example.com>", "Jane Roe <roe@example.com>"}, options)
+					return options[1], nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", "jane"), httpmock.StringResponse(janesJSON))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": "43"}`,
+			expectOut:  "assigned PROJ-1 to Jane Roe\n",
+		},
+		{
+			name: "should fail on several matching users when not interactive",
+			args: []string{"PROJ-1", "jane"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", "jane"), httpmock.StringResponse(janesJSON))
+			},
+			expectErr: `"jane" matches several users: Jane Doe <jane@example.com>, Jane Roe <roe@example.com>`,
+		},
+		{
+			name: "should fail when no user can be assigned",
+			args: []string{"PROJ-1", "bob"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", "bob"), httpmock.StringResponse(`[]`))
+			},
+			expectErr: `no user matching "bob" can be assigned to PROJ-1`,
+		},
+		{
+			name: "should assign issue of current branch to current user",
+			args: []string{"@me"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-7", url.Values{"fields": []string{"summary"}}), httpmock.StringResponse(`{"key": "PROJ-7"}`))
+				r.Register(httpmock.REST("GET", "rest/api/3/myself"),
+					httpmock.StringResponse(`{"accountId": "7", "displayName": "Me Myself"}`))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-7/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": "7"}`,
+			expectOut:  "assigned PROJ-7 to Me Myself\n",
+		},
+		{
+			name: "should unassign issue",
+			args: []string{"PROJ-1", "None"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": null}`,
+			expectOut:  "unassigned PROJ-1\n",
+		},
+		{
+			name: "should pick assignee among assignable users",
+			args: []string{"PROJ-1"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				SelectFunc: func(message string, options []string) (string, error) {
+					assert.Equal(t, "Assign PROJ-1 to", message)
+					assert.Equal(t, "Unassigned", options[len(options)-1])
+					return options[0], nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(assignableSearch("PROJ-1", ""), httpmock.StringResponse(janesJSON))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(body))
+			},
+			expectBody: `{"accountId": "42"}`,
+			expectOut:  "assigned PROJ-1 to Jane Doe\n",
+		},
+		{
+			name:      "should require user when not interactive",
+			args:      []string{"PROJ-1"},
+			stub:      func(r *httpmock.Registry, body *json.RawMessage) {},
+			expectErr: "user is required when not running interactively",
+		},
+		{
+			name: "should return api error",
+			args: []string{"PROJ-1", "none"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"),
+					httpmock.StatusStringResponse(403, `{"errorMessages":["You do not have permission to assign issues."]}`),
+				)
+			},
+			expectErr: "cannot assign PROJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			t.Setenv(config.JhCacheDir, t.TempDir())
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			var body json.RawMessage
+			tt.stub(reg, &body)
+
+			out, cmd := newTestCmd(reg, tt.tty, tt.prompter)
+			cmd.SetArgs(tt.args)
+
+			// when
+			_, err := cmd.ExecuteC()
+
+			// then
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+			if tt.expectBody != "" {
+				assert.JSONEq(t, tt.expectBody, string(body))
+			}
+		})
+	}
+}
+
+func TestAssignCachesUsers(t *testing.T) {
+	// given
+	t.Setenv(config.JhCacheDir, t.TempDir())
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	var body json.RawMessage
+	reg.Register(assignableSearch("PROJ-1", "Jane"),
+		httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(&body))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-2/assignee"), bodyRecorder(&body))
+
+	// when
+	_, first := newTestCmd(reg, false, nil)
+	first.SetArgs([]string{"PROJ-1", "Jane"})
+	_, err := first.ExecuteC()
+	assert.NoError(t, err)
+
+	out, second := newTestCmd(reg, false, nil)
+	second.SetArgs([]string{"PROJ-2", "jane"})
+	_, err = second.ExecuteC()
+
+	// then search is registered once, second lookup comes from cache
+	assert.NoError(t, err)
+	assert.Equal(t, "assigned PROJ-2 to Jane Doe\n", out.String())
+	assert.JSONEq(t, `{"accountId": "42"}`, string(body))
+}
+
+func TestAssignCachesOnlyUnambiguousUsersPerProject(t *testing.T) {
+	// given
+	t.Setenv(config.JhCacheDir, t.TempDir())
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	var body json.RawMessage
+	reg.Register(assignableSearch("PROJ-1", "jane"), httpmock.StringResponse(janesJSON))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/assignee"), bodyRecorder(&body))
+	reg.Register(assignableSearch("PROJ-2", "jane"), httpmock.StringResponse(janesJSON))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-2/assignee"), bodyRecorder(&body))
+	reg.Register(assignableSearch("OPS-1", "roe@example.com"), httpmock.StringResponse(janesJSON))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/OPS-1/assignee"), bodyRecorder(&body))
+	reg.Register(assignableSearch("OTHER-1", "roe@example.com"), httpmock.StringResponse(janesJSON))
+	reg.Register(httpmock.REST("PUT", "rest/api/3/issue/OTHER-1/assignee"), bodyRecorder(&body))
+	picker := &prompt.PrompterMock{
+		SelectFunc: func(message string, options []string) (string, error) {
+			return options[1], nil
+		},
+	}
+
+	// when picked user is assigned twice and exactly matching user in two projects
+	for _, args := range [][]string{
+		{"PROJ-1", "jane"}, {"PROJ-2", "jane"}, {"OPS-1", "roe@example.com"}, {"OTHER-1", "roe@example.com"},
+	} {
+		out, cmd := newTestCmd(reg, true, picker)
+		cmd.SetArgs(args)
+		_, err := cmd.ExecuteC()
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "assigned "+args[0]+" to Jane Roe\n", out.String())
+	}
+	// every search is registered, so picked user and user of other project are searched again
+	assert.Len(t, picker.SelectCalls(), 2)
+}
+
+func newTestCmd(reg *httpmock.Registry, tty bool, prompter *prompt.PrompterMock) (*bytes.Buffer, *cobra.Command) {
+	out := &bytes.Buffer{}
+	ios := &iostreams.IOStream{In: strings.NewReader(""), Out: out}
+	ios.SetNeverPrompt(!tty)
+
+	gitClient := gitclient.NewGitClientMock()
+	gitClient.CurrentBranchFunc = func() (string, error) {
+		return "task/proj-7-fix-login", nil
+	}
+	// prompter without stubs panics on any prompt
+	if prompter == nil {
+		prompter = &prompt.PrompterMock{}
+	}
+	f := &factory.Factory{
+		Config: func() (config.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		JiraClient: func() (*jira.Client, error) {
+			return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+		},
+		GitClient: func() (gitclient.GitClient, error) {
+			return gitClient, nil
+		},
+		Prompter: prompter,
+		IOStream: ios,
+	}
+
+	cmd := NewAssignCmd(f)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	return out, cmd
+}
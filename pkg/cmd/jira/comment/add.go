@@ -0,0 +1,70 @@
+package comment
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+)
+
+type AddOptions struct {
+	*CommentOptions
+	BodyOptions
+}
+
+func newAddCmd(co *CommentOptions) *cobra.Command {
+	ops := &AddOptions{CommentOptions: co}
+
+	cmd := &cobra.Command{
+		Use:   "add [<jira-key>]",
+		Short: "Add comment to jira issue",
+		Long: heredoc.Doc(`
+			Add comment to jira issue. Body is given by flag, read from file or
+			written in editor. When stdin is not a terminal, body is read from stdin.
+		`),
+		Example: heredoc.Doc(`
+			$ jh comment add PROJ-1 --body "Deployed to **staging**, @jane please check"
+			$ jh comment add PROJ-1 --body-file notes.md
+			$ git log -1 --format=%B | jh comment add PROJ-1
+
+			# comment issue of current branch in editor
+			$ jh comment add
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ops.resolveKey(args); err != nil {
+				return err
+			}
+			return runAdd(ops)
+		},
+	}
+
+	addBodyFlags(cmd, &ops.BodyOptions)
+
+	return cmd
+}
+
+func runAdd(ops *AddOptions) error {
+	body, err := readBody(ops.IOStream, ops.Prompter, &ops.BodyOptions, "")
+	if err != nil {
+		return err
+	}
+
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	doc := adf.FromMarkdown(body, users.NewMentionResolver(jiraClient))
+	comment, err := issues.AddComment(jiraClient, ops.JiraIssueKey, doc)
+	if err != nil {
+		return fmt.Errorf("cannot add comment to %s: %w", ops.JiraIssueKey, err)
+	}
+
+	fmt.Fprintf(ops.IOStream.Out, "added comment: %s\n",
+		issues.CommentURL(jiraClient.BaseURL.String(), ops.JiraIssueKey, comment.ID))
+	return nil
+}
@@ -0,0 +1,134 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// CommentOptions are shared by comment subcommands
+type CommentOptions struct {
+	JiraIssueKey string
+	JiraClient   func() (*jira.Client, error)
+	IOStream     *iostreams.IOStream
+	Prompter     prompt.Prompter
+	// KeyResolver finds issue key in current branch, when it is not given
+	KeyResolver *issues.KeyResolver
+}
+
+// BodyOptions are sources of comment body, body is written in markdown
+type BodyOptions struct {
+	Body string
+	// BodyFile is a path to markdown file with body, "-" means stdin
+	BodyFile  string
+	UseEditor bool
+}
+
+func NewCommentCmd(f *factory.Factory) *cobra.Command {
+	ops := &CommentOptions{
+		JiraClient:  f.JiraClient,
+		IOStream:    f.IOStream,
+		Prompter:    f.Prompter,
+		KeyResolver: issues.NewKeyResolver(f),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Manage comments of jira issue",
+		Long: heredoc.Doc(`
+			Add, list, edit and delete comments of jira issue.
+
+			Comments are written in markdown, @name mentions a user found by name or email.
+			Without key, issue key is taken from current git branch.
+		`),
+		Example: heredoc.Doc(`
+			$ jh comment add PROJ-1 --body "Fixed in **1.2**, thanks @jane"
+			$ jh comment list PROJ-1
+			$ jh comment edit PROJ-1 10001 --editor
+			$ jh comment delete PROJ-1 10001
+		`),
+	}
+
+	cmd.AddCommand(newAddCmd(ops))
+	cmd.AddCommand(newListCmd(ops))
+	cmd.AddCommand(newEditCmd(ops))
+	cmd.AddCommand(newDeleteCmd(ops))
+
+	return cmd
+}
+
+// resolveKey sets issue key given as argument or found in current branch
+func (ops *CommentOptions) resolveKey(args []string) error {
+	key, err := ops.KeyResolver.Resolve(args)
+	if err != nil {
+		return err
+	}
+	ops.JiraIssueKey = key
+	return nil
+}
+
+// resolveKeyAndID splits arguments [<jira-key>] <comment-id>
+func (ops *CommentOptions) resolveKeyAndID(args []string) (string, error) {
+	id := args[len(args)-1]
+	if issues.IsKey(id) {
+		return "", fmt.Errorf("comment id is required, %q is an issue key", id)
+	}
+	return id, ops.resolveKey(args[:len(args)-1])
+}
+
+func addBodyFlags(cmd *cobra.Command, bo *BodyOptions) {
+	cmd.Flags().StringVarP(&bo.Body, "body", "b", "", "Comment body in markdown")
+	cmd.Flags().StringVarP(&bo.BodyFile, "body-file", "F", "", "Read markdown body from file (use \"-\" to read from stdin)")
+	cmd.Flags().BoolVarP(&bo.UseEditor, "editor", "e", false, "Write body in $VISUAL or $EDITOR")
+	cmd.MarkFlagsMutuallyExclusive("body", "body-file", "editor")
+}
+
+// readBody returns comment body in markdown from flag, file, stdin or editor.
+// Without flags body is written in editor in terminal, otherwise it is read from stdin.
+// Current body is offered for editing in editor.
+func readBody(ios *iostreams.IOStream, prompter prompt.Prompter, bo *BodyOptions, current string) (string, error) {
+	var body string
+	switch {
+	case bo.Body != "":
+		body = bo.Body
+	case bo.BodyFile == "-" || bo.BodyFile == "" && !bo.UseEditor && !ios.CanPrompt():
+		b, err := io.ReadAll(ios.In)
+		if err != nil {
+			return "", fmt.Errorf("cannot read comment from stdin: %w", err)
+		}
+		body = string(b)
+	case bo.BodyFile != "":
+		b, err := os.ReadFile(bo.BodyFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read comment file: %w", err)
+		}
+		body = string(b)
+	default:
+		if !ios.CanPrompt() {
+			return "", errors.New("stdin is not a terminal, so jh cannot open an editor. Use --body or --body-file instead")
+		}
+		text, err := prompter.Editor("Comment", current+adf.EditorHint)
+		if err != nil {
+			return "", err
+		}
+		body = adf.StripComments(text)
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body is empty")
+	}
+	return body, nil
+}
@@ -0,0 +1,315 @@
+package comment
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+const commentsJSON = `{"startAt": 0, "total": 3, "comments": [
+	{"id": "10003", "author": {"displayName": "Jane Doe"}, "created": "2022-11-03T10:00:00.000+0000",
+		"updated": "2022-11-03T12:00:00.000+0000", "parentId": 10001,
+		"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "done"}]}]}},
+	{"id": "10002", "author": {"displayName": "John Roe"}, "created": "2022-11-02T10:00:00.000+0000",
+		"updated": "2022-11-02T10:00:00.000+0000",
+		"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "second"}]}]}}
+]}`
+
+// bodyRecorder records json body of request and responds with given body
+func bodyRecorder(body *json.RawMessage, status int, response string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		_ = json.NewDecoder(req.Body).Decode(body)
+		return httpmock.StatusStringResponse(status, response)(req)
+	}
+}
+
+// doc returns json of document with a single paragraph
+func doc(text string) string {
+	return `{"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "` + text + `"}]}]}`
+}
+
+func paragraph(text string) string {
+	return `{"body": ` + doc(text) + `}`
+}
+
+// TestMain fixes local time zone, times of issues are printed in it
+func TestMain(m *testing.M) {
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+func TestComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		tty        bool
+		stdin      string
+		prompter   *prompt.PrompterMock
+		stub       func(r *httpmock.Registry, body *json.RawMessage)
+		expectBody string
+		expectOut  string
+		expectErr  string
+	}{
+		{
+			name: "should add comment with mention",
+			args: []string{"add", "proj-1", "--body", "thanks @jane"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"jane"}}),
+					httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`),
+				)
+				r.Register(httpmock.REST("POST", "rest/api/3/issue/PROJ-1/comment"), bodyRecorder(body, 201, `{"id": "10004"}`))
+			},
+			expectBody: `{"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "thanks "}, {"type": "mention", "attrs": {"id": "42", "text": "@jane"}}]}]}}`,
+			expectOut: "added comment: https://jira-url/browse/PROJ-1?focusedCommentId=10004\n",
+		},
+		{
+			name:  "should add comment read from stdin to issue of current branch",
+			args:  []string{"add"},
+			stdin: "  from stdin\n",
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-7", url.Values{"fields": []string{"summary"}}), httpmock.StringResponse(`{"key": "PROJ-7"}`))
+				r.Register(httpmock.REST("POST", "rest/api/3/issue/PROJ-7/comment"), bodyRecorder(body, 201, `{"id": "10004"}`))
+			},
+			expectBody: paragraph("from stdin"),
+			expectOut:  "added comment: https://jira-url/browse/PROJ-7?focusedCommentId=10004\n",
+		},
+		{
+			name: "should add comment written in editor",
+			args: []string{"add", "PROJ-1"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				EditorFunc: func(message, text string) (string, error) {
+					return "in editor\n" + text, nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.REST("POST", "rest/api/3/issue/PROJ-1/comment"), bodyRecorder(body, 201, `{"id": "10004"}`))
+			},
+			expectBody: paragraph("in editor"),
+			expectOut:  "added comment: https://jira-url/browse/PROJ-1?focusedCommentId=10004\n",
+		},
+		{
+			name:      "should reject empty comment",
+			args:      []string{"add", "PROJ-1"},
+			stdin:     "\n",
+			stub:      func(r *httpmock.Registry, body *json.RawMessage) {},
+			expectErr: "comment body is empty",
+		},
+		{
+			name: "should list latest comments with replies under their parents",
+			args: []string{"list", "PROJ-1", "--limit", "2"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1/comment", url.Values{
+						"orderBy": []string{"-created"}, "startAt": []string{"0"}, "maxResults": []string{"2"},
+					}),
+					httpmock.StringResponse(commentsJSON),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				Latest 2 of 3 comments on PROJ-1
+
+				John Roe 2022-11-02 10:00 #10002
+				│ second
+
+				Jane Doe 2022-11-03 10:00 (edited) #10003
+				│ done
+			`),
+		},
+		{
+			name: "should page through all comments",
+			args: []string{"list", "PROJ-1", "--limit", "0", "--jq", ".[].id"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1/comment", url.Values{"startAt": []string{"2"}}),
+					httpmock.StringResponse(`{"startAt": 2, "total": 3, "comments": [{"id": "10001", "created": "2022-11-01T10:00:00.000+0000"}]}`),
+				)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1/comment", url.Values{"startAt": []string{"0"}, "maxResults": []string{"100"}}),
+					httpmock.StringResponse(commentsJSON),
+				)
+			},
+			expectOut: "10001\n10002\n10003\n",
+		},
+		{
+			name: "should edit comment in editor",
+			args: []string{"edit", "PROJ-1", "10002"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				EditorFunc: func(message, text string) (string, error) {
+					return strings.Replace(text, "second", "second, updated", 1), nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1/comment/10002"),
+					httpmock.StringResponse(`{"id": "10002", "body": `+doc("second")+`}`),
+				)
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/comment/10002"), bodyRecorder(body, 200, `{"id": "10002"}`))
+			},
+			expectBody: paragraph("second, updated"),
+			expectOut:  "edited comment: https://jira-url/browse/PROJ-1?focusedCommentId=10002\n",
+		},
+		{
+			name: "should edit mentions by account id",
+			args: []string{"edit", "PROJ-1", "10002"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				EditorFunc: func(message, text string) (string, error) {
+					assert.Contains(t, text, "ask @[acc-1]\n")
+					return strings.Replace(text, "ask", "thanks", 1), nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1/comment/10002"),
+					httpmock.StringResponse(`{"id": "10002", "body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [
+						{"type": "text", "text": "ask "}, {"type": "mention", "attrs": {"id": "acc-1", "text": "@John Doe"}}]}]}}`),
+				)
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-1/comment/10002"), bodyRecorder(body, 200, `{"id": "10002"}`))
+			},
+			expectBody: `{"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [
+				{"type": "text", "text": "thanks "}, {"type": "mention", "attrs": {"id": "acc-1"}}]}]}}`,
+			expectOut: "edited comment: https://jira-url/browse/PROJ-1?focusedCommentId=10002\n",
+		},
+		{
+			name: "should not edit comment with table when it is not confirmed",
+			args: []string{"edit", "PROJ-1", "10002"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				ConfirmFunc: func(message string) (bool, error) {
+					assert.Equal(t, "Comment 10002 has table, tableCell, tableRow, which can't be edited as markdown and would be lost. Edit anyway?", message)
+					return false, nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1/comment/10002"),
+					httpmock.StringResponse(`{"id": "10002", "body": {"type": "doc", "version": 1, "content": [{"type": "table", "content": [
+						{"type": "tableRow", "content": [{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "a"}]}]}]}]}]}}`),
+				)
+			},
+		},
+		{
+			name: "should edit comment of current branch issue by flag",
+			args: []string{"edit", "10002", "-b", "new"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-7", url.Values{"fields": []string{"summary"}}), httpmock.StringResponse(`{"key": "PROJ-7"}`))
+				r.Register(httpmock.REST("PUT", "rest/api/3/issue/PROJ-7/comment/10002"), bodyRecorder(body, 200, `{"id": "10002"}`))
+			},
+			expectBody: paragraph("new"),
+			expectOut:  "edited comment: https://jira-url/browse/PROJ-7?focusedCommentId=10002\n",
+		},
+		{
+			name:      "should require comment id",
+			args:      []string{"edit", "PROJ-1"},
+			stub:      func(r *httpmock.Registry, body *json.RawMessage) {},
+			expectErr: `comment id is required, "PROJ-1" is an issue key`,
+		},
+		{
+			name: "should delete comment after confirmation",
+			args: []string{"delete", "PROJ-1", "10002"},
+			tty:  true,
+			prompter: &prompt.PrompterMock{
+				ConfirmFunc: func(message string) (bool, error) {
+					assert.Equal(t, "Delete comment 10002 of PROJ-1?", message)
+					return true, nil
+				},
+			},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(httpmock.REST("DELETE", "rest/api/3/issue/PROJ-1/comment/10002"), httpmock.StatusStringResponse(204, ""))
+			},
+			expectOut: "deleted comment 10002 of PROJ-1\n",
+		},
+		{
+			name:      "should require --yes to delete when not interactive",
+			args:      []string{"delete", "PROJ-1", "10002"},
+			stub:      func(r *httpmock.Registry, body *json.RawMessage) {},
+			expectErr: "--yes is required to delete comment when not running interactively",
+		},
+		{
+			name: "should return api error",
+			args: []string{"rm", "PROJ-1", "10009", "--yes"},
+			stub: func(r *httpmock.Registry, body *json.RawMessage) {
+				r.Register(
+					httpmock.REST("DELETE", "rest/api/3/issue/PROJ-1/comment/10009"),
+					httpmock.StatusStringResponse(404, `{"errorMessages":["Can not find a comment for the id: 10009."]}`),
+				)
+			},
+			expectErr: "cannot delete comment 10009 of PROJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			var body json.RawMessage
+			tt.stub(reg, &body)
+
+			out := &bytes.Buffer{}
+			ios := &iostreams.IOStream{In: strings.NewReader(tt.stdin), Out: out}
+			ios.SetNeverPrompt(!tt.tty)
+
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CurrentBranchFunc = func() (string, error) {
+				return "task/proj-7-fix-login", nil
+			}
+			// prompter without stubs panics on any prompt
+			prompter := tt.prompter
+			if prompter == nil {
+				prompter = &prompt.PrompterMock{}
+			}
+			f := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				Prompter: prompter,
+				IOStream: ios,
+			}
+
+			cmd := NewCommentCmd(f)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			// when
+			_, err := cmd.ExecuteC()
+
+			// then
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+			if tt.expectBody != "" {
+				assert.JSONEq(t, tt.expectBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+type DeleteOptions struct {
+	*CommentOptions
+	CommentID string
+	// Yes skips confirmation
+	Yes bool
+}
+
+func newDeleteCmd(co *CommentOptions) *cobra.Command {
+	ops := &DeleteOptions{CommentOptions: co}
+
+	cmd := &cobra.Command{
+		Use:     "delete [<jira-key>] <comment-id>",
+		Aliases: []string{"rm"},
+		Short:   "Delete comment of jira issue",
+		Long: heredoc.Doc(`
+			Delete comment of jira issue, deletion is confirmed in terminal unless --yes is given.
+			Comment ids are shown by jh comment list.
+		`),
+		Example: heredoc.Doc(`
+			$ jh comment delete PROJ-1 10001
+			$ jh comment rm 10001 --yes
+		`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := ops.resolveKeyAndID(args)
+			if err != nil {
+				return err
+			}
+			ops.CommentID = id
+			return runDelete(ops)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&ops.Yes, "yes", "y", false, "Delete without confirmation")
+
+	return cmd
+}
+
+func runDelete(ops *DeleteOptions) error {
+	if !ops.Yes {
+		if !ops.IOStream.CanPrompt() {
+			return errors.New("--yes is required to delete comment when not running interactively")
+		}
+		ok, err := ops.Prompter.Confirm(fmt.Sprintf("Delete comment %s of %s?", ops.CommentID, ops.JiraIssueKey))
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodDelete,
+		issues.CommentPath(ops.JiraIssueKey, ops.CommentID), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return fmt.Errorf("cannot delete comment %s of %s: %w", ops.CommentID, ops.JiraIssueKey, jira.NewJiraError(resp, err))
+	}
+
+	fmt.Fprintf(ops.IOStream.Out, "deleted comment %s of %s\n", ops.CommentID, ops.JiraIssueKey)
+	return nil
+}
@@ -0,0 +1,116 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+type EditOptions struct {
+	*CommentOptions
+	BodyOptions
+	CommentID string
+}
+
+func newEditCmd(co *CommentOptions) *cobra.Command {
+	ops := &EditOptions{CommentOptions: co}
+
+	cmd := &cobra.Command{
+		Use:   "edit [<jira-key>] <comment-id>",
+		Short: "Edit comment of jira issue",
+		Long: heredoc.Doc(`
+			Replace body of comment. Without body flags, current body is
+			offered for editing in editor, or new body is read from stdin
+			when it is not a terminal. Comment ids are shown by jh comment list.
+
+			Mentions are edited as @[account id]. Before editing comment with
+			content markdown can't express, e.g. tables or panels, you are asked
+			to confirm that it is lost.
+		`),
+		Example: heredoc.Doc(`
+			$ jh comment edit PROJ-1 10001
+			$ jh comment edit PROJ-1 10001 --body "Fixed in **1.3**"
+		`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := ops.resolveKeyAndID(args)
+			if err != nil {
+				return err
+			}
+			ops.CommentID = id
+			return runEdit(ops)
+		},
+	}
+
+	addBodyFlags(cmd, &ops.BodyOptions)
+
+	return cmd
+}
+
+func runEdit(ops *EditOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	if ops.Body == "" && ops.BodyFile == "" && ops.IOStream.CanPrompt() {
+		comment, err := getComment(jiraClient, ops.JiraIssueKey, ops.CommentID)
+		if err != nil {
+			return err
+		}
+		// e.g. tables or panels would be replaced by their text
+		if unsupported := adf.Unsupported(comment.Body); len(unsupported) > 0 {
+			ok, err := ops.Prompter.Confirm(fmt.Sprintf("Comment %s has %s, which can't be edited as markdown and would be lost. Edit anyway?",
+				ops.CommentID, strings.Join(unsupported, ", ")))
+			if err != nil || !ok {
+				return err
+			}
+		}
+		current = adf.ToMarkdown(comment.Body) + "\n"
+	}
+
+	body, err := readBody(ops.IOStream, ops.Prompter, &ops.BodyOptions, current)
+	if err != nil {
+		return err
+	}
+
+	doc := adf.FromMarkdown(body, users.NewMentionResolver(jiraClient))
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPut,
+		issues.CommentPath(ops.JiraIssueKey, ops.CommentID), map[string]interface{}{"body": doc})
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return fmt.Errorf("cannot edit comment %s of %s: %w", ops.CommentID, ops.JiraIssueKey, jira.NewJiraError(resp, err))
+	}
+
+	fmt.Fprintf(ops.IOStream.Out, "edited comment: %s\n",
+		issues.CommentURL(jiraClient.BaseURL.String(), ops.JiraIssueKey, ops.CommentID))
+	return nil
+}
+
+func getComment(jiraClient *jira.Client, key, id string) (*issues.Comment, error) {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet, issues.CommentPath(key, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &issues.Comment{}
+	resp, err := jiraClient.Do(req, comment)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get comment %s of %s: %w", id, key, jira.NewJiraError(resp, err))
+	}
+	return comment, nil
+}
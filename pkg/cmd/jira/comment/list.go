@@ -0,0 +1,147 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/export"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+const (
+	// defaultLimit is a number of latest comments listed when --limit is not given
+	defaultLimit = 20
+	// pageSize is a max number of comments jira returns per page
+	pageSize = 100
+)
+
+type ListOptions struct {
+	*CommentOptions
+	// Limit is a number of latest comments listed, all comments are listed when it is 0
+	Limit int
+
+	// Exporter prints comments as json array, or formats them by jq expression or go template
+	Exporter export.Options
+}
+
+type commentsPage struct {
+	Comments []*issues.Comment `json:"comments"`
+	StartAt  int               `json:"startAt"`
+	Total    int               `json:"total"`
+}
+
+func newListCmd(co *CommentOptions) *cobra.Command {
+	ops := &ListOptions{CommentOptions: co}
+
+	cmd := &cobra.Command{
+		Use:     "list [<jira-key>]",
+		Aliases: []string{"ls"},
+		Short:   "List comments of jira issue",
+		Long: heredoc.Doc(`
+			List latest comments of jira issue, oldest first. Replies are shown under
+			comments they answer, every comment is shown with its id, e.g. #10001.
+		`),
+		Example: heredoc.Doc(`
+			$ jh comment list PROJ-1
+			$ jh comment ls --limit 0
+
+			$ jh comment list PROJ-1 --json=id,author,created
+			$ jh comment list PROJ-1 --jq '.[] | select(.author.displayName == "Jane Doe") | .id'
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ops.Limit < 0 {
+				return fmt.Errorf("invalid --limit %d, it must not be negative", ops.Limit)
+			}
+			if err := ops.resolveKey(args); err != nil {
+				return err
+			}
+			return runList(ops)
+		},
+	}
+
+	cmd.Flags().IntVarP(&ops.Limit, "limit", "L", defaultLimit, "Maximum number of latest comments, 0 lists all")
+	export.AddFlags(cmd, &ops.Exporter, export.TemplateFlag)
+
+	return cmd
+}
+
+func runList(ops *ListOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	comments, total, err := getLatestComments(jiraClient, ops.JiraIssueKey, ops.Limit)
+	if err != nil {
+		return fmt.Errorf("cannot get comments of %s: %w", ops.JiraIssueKey, err)
+	}
+
+	if ops.Exporter.Enabled() {
+		return ops.Exporter.Write(ops.IOStream, comments)
+	}
+
+	out, cs := ops.IOStream.Out, ops.IOStream.ColorScheme()
+	if len(comments) == 0 {
+		fmt.Fprintf(out, "%s has no comments\n", ops.JiraIssueKey)
+		return nil
+	}
+
+	title := fmt.Sprintf("%d comments on %s", total, ops.JiraIssueKey)
+	if total > len(comments) {
+		title = fmt.Sprintf("Latest %d of %d comments on %s", len(comments), total, ops.JiraIssueKey)
+	}
+	fmt.Fprintln(out, cs.Bold(title))
+	issues.PrintThreads(out, cs, comments, true)
+	return nil
+}
+
+// getLatestComments returns at most limit latest comments, oldest first,
+// along with total number of comments. All comments are returned when limit is 0.
+func getLatestComments(jiraClient *jira.Client, key string, limit int) ([]*issues.Comment, int, error) {
+	var latest []*issues.Comment
+	total := 0
+	for {
+		size := pageSize
+		if limit > 0 && limit-len(latest) < size {
+			size = limit - len(latest)
+		}
+
+		values := url.Values{}
+		values.Set("orderBy", "-created")
+		values.Set("startAt", strconv.Itoa(len(latest)))
+		values.Set("maxResults", strconv.Itoa(size))
+
+		req, err := jiraClient.NewRequest(context.Background(), http.MethodGet,
+			issues.CommentPath(key, "")+"?"+values.Encode(), nil)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		page := &commentsPage{}
+		resp, err := jiraClient.Do(req, page)
+		if err != nil {
+			return nil, 0, jira.NewJiraError(resp, err)
+		}
+
+		latest = append(latest, page.Comments...)
+		total = page.Total
+		if len(page.Comments) == 0 || len(latest) >= total || (limit > 0 && len(latest) >= limit) {
+			break
+		}
+	}
+
+	// comments are read newest first
+	comments := make([]*issues.Comment, 0, len(latest))
+	for i := len(latest) - 1; i >= 0; i-- {
+		comments = append(comments, latest[i])
+	}
+	return comments, total, nil
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/auth"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+)
+
+type ConfigOptions struct {
+	Config    func() (config.Config, error)
+	GitClient func() (gitclient.GitClient, error)
+	Out       io.Writer
+	ErrOut    io.Writer
+
+	// Local makes command work with .jh.yml of current git repository
+	Local bool
+	Key   string
+	Value string
+}
+
+func NewConfigCmd(f *factory.Factory) *cobra.Command {
+	ops := &ConfigOptions{
+		Config:    f.Config,
+		GitClient: f.GitClient,
+		Out:       f.IOStream.Out,
+		ErrOut:    f.IOStream.ErrOut,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage jh configuration",
+		Long: heredoc.Doc(`
+			Manage jh configuration.
+
+			User configuration is stored in ~/.config/jh/config.yml. Settings under "configuration"
+			can be overridden per repository in .jh.yml at the root of git repository,
+			e.g. to use different default project in every repository. Without --local, set changes
+			user configuration even when .jh.yml overrides the key.
+		`),
+		Example: heredoc.Doc(`
+			# use project BE for issues created in current repository
+			$ jh config set --local configuration.issue.projectKey BE
+
+			# add labels to every issue created in current repository
+			$ jh config set --local configuration.issue.labels backend,api
+
+			# print value in effect for current directory
+			$ jh config get configuration.issue.projectKey
+		`),
+	}
+
+	// configuration can be changed before authentication
+	auth.DisableAuthCheck(cmd)
+
+	cmd.PersistentFlags().BoolVar(&ops.Local, "local", false, "Use .jh.yml of current git repository")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print configuration value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ops.Key = args[0]
+			return runGet(ops)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Update configuration value",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ops.Key, ops.Value = args[0], args[1]
+			return runSet(ops)
+		},
+	})
+
+	return cmd
+}
+
+func runGet(ops *ConfigOptions) error {
+	cfg, err := selectConfig(ops)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err = cfg.Decode(strings.Split(ops.Key, "."), &value); err != nil {
+		var notFound config.KeyNotFoundError
+		if errors.As(err, &notFound) {
+			return fmt.Errorf("%s is not set", ops.Key)
+		}
+		return err
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			fmt.Fprintln(ops.Out, item)
+		}
+	case map[string]interface{}:
+		return fmt.Errorf("%s is a section, get one of its keys instead", ops.Key)
+	case nil:
+		fmt.Fprintln(ops.Out)
+	default:
+		fmt.Fprintln(ops.Out, v)
+	}
+
+	return nil
+}
+
+func runSet(ops *ConfigOptions) error {
+	keys := strings.Split(ops.Key, ".")
+	if ops.Local && keys[0] != "configuration" {
+		return fmt.Errorf("only keys under \"configuration\" can be set in %s", config.LocalConfigFileName)
+	}
+
+	cfg, err := selectConfig(ops)
+	if err != nil {
+		return err
+	}
+
+	cfg.SetNested(keys, ops.Value)
+	if err = cfg.Write(); err != nil {
+		return err
+	}
+
+	if !ops.Local && shadowedByLocal(ops, keys) {
+		fmt.Fprintf(ops.ErrOut, "warning: %s is also set in %s, which takes precedence in this repository, use --local to change it\n",
+			ops.Key, config.LocalConfigFileName)
+	}
+
+	return nil
+}
+
+// shadowedByLocal reports whether key is set in .jh.yml of current repository
+func shadowedByLocal(ops *ConfigOptions, keys []string) bool {
+	if keys[0] != "configuration" {
+		return false
+	}
+
+	gitClient, err := ops.GitClient()
+	if err != nil {
+		return false
+	}
+	root, err := gitClient.RootDir()
+	if err != nil {
+		return false
+	}
+	local, err := config.NewLocalConfig(config.LocalConfigFile(root))
+	if err != nil {
+		return false
+	}
+
+	var value interface{}
+	return local.Decode(keys, &value) == nil
+}
+
+// selectConfig returns either config in effect or config of current repository only
+func selectConfig(ops *ConfigOptions) (config.Config, error) {
+	if !ops.Local {
+		return ops.Config()
+	}
+
+	gitClient, err := ops.GitClient()
+	if err != nil {
+		return nil, err
+	}
+	root, err := gitClient.RootDir()
+	if err != nil {
+		return nil, fmt.Errorf("--local can be used only inside git repository: %w", err)
+	}
+
+	return config.NewLocalConfig(config.LocalConfigFile(root))
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/go-git/go-git/v5"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func runConfigCommand(f *factory.Factory, args ...string) error {
+	cmd := NewConfigCmd(f)
+	cmd.SetArgs(args)
+
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	_, err := cmd.ExecuteC()
+	return err
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		localConfig string
+		noRepo      bool
+		expectOut   string
+		expectWarn  string
+		expectLocal string
+		expectErr   string
+	}{
+		{
+			name:        "should write to repository config",
+			args:        []string{"set", "--local", "configuration.issue.projectKey", "MOB"},
+			expectLocal: "configuration:\n    issue:\n        projectKey: MOB\n",
+		},
+		{
+			name:        "should warn when repository config overrides user value",
+			args:        []string{"set", "configuration.issue.projectKey", "OPS"},
+			localConfig: "configuration:\n  issue:\n    projectKey: MOB\n",
+			expectWarn:  "warning: configuration.issue.projectKey is also set in .jh.yml, which takes precedence in this repository, use --local to change it\n",
+		},
+		{
+			name:      "should not write credentials to repository config",
+			args:      []string{"set", "--local", "url", "https://example.com"},
+			expectErr: `only keys under "configuration" can be set in .jh.yml`,
+		},
+		{
+			name:        "should print repository value",
+			args:        []string{"get", "--local", "configuration.issue.labels"},
+			localConfig: "configuration:\n  issue:\n    labels: [mobile, ios]\n",
+			expectOut:   "mobile\nios\n",
+		},
+		{
+			name:      "should print value in effect",
+			args:      []string{"get", "configuration.issue.projectKey"},
+			expectOut: "BE\n",
+		},
+		{
+			name:      "should report missing value",
+			args:      []string{"get", "--local", "configuration.issue.projectKey"},
+			expectErr: "configuration.issue.projectKey is not set",
+		},
+		{
+			name:      "should require git repository for local config",
+			args:      []string{"get", "--local", "configuration.issue.projectKey"},
+			noRepo:    true,
+			expectErr: "--local can be used only inside git repository: repository does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			root := t.TempDir()
+			localPath := filepath.Join(root, config.LocalConfigFileName)
+			if tt.localConfig != "" {
+				assert.NoError(t, os.WriteFile(localPath, []byte(tt.localConfig), 0644))
+			}
+
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.RootDirFunc = func() (string, error) {
+				if tt.noRepo {
+					return "", git.ErrRepositoryNotExists
+				}
+				return root, nil
+			}
+
+			cfg := config.NewFromString(heredoc.Doc(`
+				configuration:
+				  issue:
+				    projectKey: BE
+			`))
+
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: &iostreams.IOStream{Out: out, ErrOut: errOut},
+			}
+
+			// when
+			err := runConfigCommand(factory, tt.args...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+			assert.Equal(t, tt.expectWarn, errOut.String())
+
+			if tt.expectLocal != "" {
+				data, err := os.ReadFile(localPath)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectLocal, string(data))
+			}
+		})
+	}
+}
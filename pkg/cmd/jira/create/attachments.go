@@ -0,0 +1,76 @@
+package create
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+)
+
+// checkAttachments makes sure files can be attached before issue is created
+func checkAttachments(paths []string) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot attach file: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("cannot attach %s: it is a directory", path)
+		}
+	}
+
+	return nil
+}
+
+// uploadAttachments attaches files to the issue one by one. Failed upload doesn't
+// stop the others, errors of failed uploads are returned.
+func uploadAttachments(jiraClient *jira.Client, ops *CreateOptions, issueKey string) []error {
+	if len(ops.Attach) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(ops.Out)
+	var errs []error
+	for _, path := range ops.Attach {
+		name := filepath.Base(path)
+		percent := -1
+		err := issues.UploadAttachment(jiraClient, issueKey, path, func(sent, total int64) {
+			if !ops.IOStream.IsOutTerminal() || total == 0 {
+				return
+			}
+			if p := int(sent * 100 / total); p != percent {
+				percent = p
+				fmt.Fprintf(ops.Out, "\r\033[Kuploading %s %3d%% (%s of %s)", name, p, formatSize(sent), formatSize(total))
+			}
+		})
+
+		if ops.IOStream.IsOutTerminal() {
+			fmt.Fprint(ops.Out, "\r\033[K")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot attach %s: %w", path, err))
+			fmt.Fprintf(ops.Out, "failed to attach %s\n", name)
+			continue
+		}
+		fmt.Fprintf(ops.Out, "attached %s\n", name)
+	}
+
+	return errs
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[exp])
+}
@@ -0,0 +1,140 @@
+package create
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+const (
+	// defaultBranchTemplate is used by --checkout when configuration.git.branchTemplate is not set
+	defaultBranchTemplate = "{type}/{key}-{summary}"
+	// summaryMaxLength limits length of {summary} placeholder
+	summaryMaxLength = 50
+)
+
+var branchPlaceholderRe = regexp.MustCompile(`{[^{}/]*}`)
+
+// branchValues holds values of branch template placeholders
+type branchValues struct {
+	key       string
+	issueType string
+	summary   string
+	user      string
+}
+
+func newBranchValues(issue *jira.Issue, user *jira.User) *branchValues {
+	v := &branchValues{key: issue.Key}
+	if issue.Fields != nil {
+		v.issueType = issue.Fields.Type.Name
+		v.summary = issue.Fields.Summary
+	}
+	if user != nil {
+		v.user = user.DisplayName
+		if i := strings.Index(user.EmailAddress, "@"); i > 0 {
+			v.user = user.EmailAddress[:i]
+		}
+	}
+	return v
+}
+
+// renderBranchName fills template placeholders and makes result a valid git ref name.
+// Supported placeholders are {key} (lowercased), {KEY}, {type}, {summary} and {user}.
+// For backward compatibility first @ is replaced with lowercased issue key.
+func renderBranchName(template string, v *branchValues) (string, error) {
+	var unknown []string
+	name := branchPlaceholderRe.ReplaceAllStringFunc(template, func(p string) string {
+		switch p {
+		case "{key}":
+			return strings.ToLower(v.key)
+		case "{KEY}":
+			return v.key
+		case "{type}":
+			return slugify(v.issueType, 0)
+		case "{summary}":
+			return slugify(v.summary, summaryMaxLength)
+		case "{user}":
+			return slugify(v.user, 0)
+		}
+		unknown = append(unknown, p)
+		return p
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown placeholder %s in branch template %q, use {key}, {KEY}, {type}, {summary} or {user}",
+			strings.Join(unknown, ", "), template)
+	}
+
+	name = sanitizeRefName(normalizeBranchName(name, v.key))
+	if name == "" {
+		return "", fmt.Errorf("branch template %q gives an empty branch name", template)
+	}
+
+	return name, nil
+}
+
+// slugify lowercases s and joins its words with dashes,
+// result is cut to maxLength at word boundary when maxLength > 0
+func slugify(s string, maxLength int) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	slug := ""
+	for _, w := range words {
+		next := w
+		if slug != "" {
+			next = slug + "-" + w
+		}
+		if maxLength > 0 && len(next) > maxLength {
+			if slug == "" {
+				// a single long word is cut
+				slug = strings.ToValidUTF8(w[:maxLength], "")
+			}
+			break
+		}
+		slug = next
+	}
+
+	return slug
+}
+
+// sanitizeRefName replaces characters not allowed in git ref names,
+// see git check-ref-format for the rules
+func sanitizeRefName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune("~^:?*[\\", r) {
+			b.WriteRune('-')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	name = b.String()
+
+	for _, s := range []struct{ old, new string }{{"@{", "@-"}, {"..", "."}, {"--", "-"}} {
+		for strings.Contains(name, s.old) {
+			name = strings.ReplaceAll(name, s.old, s.new)
+		}
+	}
+
+	var components []string
+	for _, c := range strings.Split(name, "/") {
+		c = strings.TrimLeft(c, ".-")
+		for strings.HasSuffix(c, ".lock") || strings.HasSuffix(c, ".") || strings.HasSuffix(c, "-") {
+			c = strings.TrimSuffix(strings.TrimSuffix(strings.TrimRight(c, ".-"), ".lock"), ".")
+		}
+		if c != "" {
+			components = append(components, c)
+		}
+	}
+
+	name = strings.Join(components, "/")
+	if name == "@" {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,86 @@
+package create
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate_renderBranchName(t *testing.T) {
+	values := &branchValues{
+		key:       "PROJ-12",
+		issueType: "Bug",
+		summary:   "Login fails: can't use `~user` names [again]... really?",
+		user:      "john.doe",
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		values    *branchValues
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "should keep @ substitution",
+			template: "feature/@",
+			expected: "feature/proj-12",
+		},
+		{
+			name:     "should fill every placeholder",
+			template: "{user}/{type}/{KEY}-{key}-{summary}",
+			expected: "john-doe/bug/PROJ-12-proj-12-login-fails-can-t-use-user-names-again-really",
+		},
+		{
+			name:     "should limit summary length at word boundary",
+			template: "{key}-{summary}",
+			values: &branchValues{
+				key:     "PROJ-1",
+				summary: "one two three four five six seven eight nine ten eleven twelve",
+			},
+			expected: "proj-1-one-two-three-four-five-six-seven-eight-nine-ten",
+		},
+		{
+			name:     "should clean characters which are not allowed in git",
+			template: "/.hidden/a..b/x.lock/ with space ~^:?*[\\/end.",
+			expected: "hidden/a.b/x/with-space/end",
+		},
+		{
+			name:      "should reject unknown placeholders",
+			template:  "{issue}/{key}",
+			expectErr: `unknown placeholder {issue} in branch template "{issue}/{key}", use {key}, {KEY}, {type}, {summary} or {user}`,
+		},
+		{
+			name:      "should reject empty result",
+			template:  "{user}",
+			values:    &branchValues{key: "PROJ-1"},
+			expectErr: `branch template "{user}" gives an empty branch name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := values
+			if tt.values != nil {
+				v = tt.values
+			}
+
+			// when
+			result, err := renderBranchName(tt.template, v)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCreate_sanitizeRefName(t *testing.T) {
+	assert.Equal(t, "feat@-u}", sanitizeRefName("feat@{u}"))
+	assert.Equal(t, "", sanitizeRefName("@"))
+	assert.Equal(t, "a/b", sanitizeRefName("a//b.lock.lock"))
+}
@@ -0,0 +1,521 @@
+package create
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+	"github.com/stirboy/jh/pkg/utils"
+	"github.com/trivago/tgo/tcontainer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// bulkBatchSize is a max number of issues jira accepts in a single bulk request
+	bulkBatchSize = 50
+	// bulkConcurrency is a max number of bulk requests sent at the same time
+	bulkConcurrency = 3
+)
+
+// bulkRow is a single issue described in input file
+type bulkRow struct {
+	Project     string                 `yaml:"project" json:"project"`
+	IssueType   string                 `yaml:"type" json:"type"`
+	Summary     string                 `yaml:"summary" json:"summary"`
+	Description string                 `yaml:"description" json:"description"`
+	Labels      []string               `yaml:"labels" json:"labels"`
+	Components  []string               `yaml:"components" json:"components"`
+	Priority    string                 `yaml:"priority" json:"priority"`
+	Assignee    string                 `yaml:"assignee" json:"assignee"`
+	Parent      string                 `yaml:"parent" json:"parent"`
+	Fields      map[string]interface{} `yaml:"fields" json:"fields"`
+
+	// result of validation and creation
+	fields *jira.IssueFields
+	key    string
+	err    error
+}
+
+// readBulkFile reads issues from csv, yaml or json file, format is chosen by file extension
+func readBulkFile(path string) ([]*bulkRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*bulkRow
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		rows, err = readBulkCSV(data)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &rows)
+	case ".json":
+		err = json.Unmarshal(data, &rows)
+	default:
+		return nil, fmt.Errorf("unsupported file format %q, use .csv, .yml, .yaml or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no issues found in %s", path)
+	}
+	for i, r := range rows {
+		if r == nil {
+			rows[i] = &bulkRow{}
+		}
+	}
+
+	return rows, nil
+}
+
+// readBulkCSV reads rows from csv with a header. Known columns map to issue values,
+// other columns are treated as fields referenced by key or name.
+func readBulkCSV(data []byte) ([]*bulkRow, error) {
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, nil
+	}
+
+	header := records[0]
+	var rows []*bulkRow
+	for _, record := range records[1:] {
+		row := &bulkRow{}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			switch column := strings.TrimSpace(header[i]); strings.ToLower(column) {
+			case "project":
+				row.Project = value
+			case "type", "issuetype", "issue type":
+				row.IssueType = value
+			case "summary":
+				row.Summary = value
+			case "description":
+				row.Description = value
+			case "labels":
+				row.Labels = splitList(value)
+			case "components":
+				row.Components = splitList(value)
+			case "priority":
+				row.Priority = value
+			case "assignee":
+				row.Assignee = value
+			case "parent":
+				row.Parent = value
+			default:
+				if row.Fields == nil {
+					row.Fields = make(map[string]interface{})
+				}
+				row.Fields[column] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func runBulk(ops *CreateOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := ops.Config()
+	if err != nil {
+		return err
+	}
+
+	rows, err := readBulkFile(ops.FromFile)
+	if err != nil {
+		return err
+	}
+
+	// values given by flags or stored defaults are used for rows which miss them
+	projectKeyValue, _ := cfg.GetNested([]string{"configuration", "issue", "projectKey"})
+	issueTypeNameValue, _ := cfg.GetNested([]string{"configuration", "issue", "issueTypeName"})
+	defaultProject := firstNonEmpty(ops.ProjectKey, projectKeyValue)
+	defaultIssueType := firstNonEmpty(ops.IssueTypeName, issueTypeNameValue)
+
+	v := &bulkValidator{
+		jiraClient: jiraClient,
+		resolve:    users.NewMentionResolver(jiraClient),
+		fields:     make(map[string]map[string]*FieldMeta),
+		fieldErrs:  make(map[string]error),
+		users:      make(map[string]*jira.User),
+	}
+	labels := defaultLabels(cfg)
+	for _, row := range rows {
+		row.Labels = mergeValues(labels, row.Labels)
+		row.Project = firstNonEmpty(row.Project, defaultProject)
+		row.IssueType = firstNonEmpty(row.IssueType, defaultIssueType)
+		row.fields, row.err = v.validate(row)
+	}
+
+	if !ops.DryRun {
+		createBulk(jiraClient, rows)
+	}
+
+	printBulkResult(ops.Out, jiraClient, rows, ops.DryRun)
+
+	failed := 0
+	for _, row := range rows {
+		if row.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		if ops.DryRun {
+			return fmt.Errorf("%d of %d issues are invalid", failed, len(rows))
+		}
+		return fmt.Errorf("%d of %d issues were not created", failed, len(rows))
+	}
+
+	return nil
+}
+
+// bulkValidator checks rows against createmeta, which is loaded once
+// for every combination of project and issue type
+type bulkValidator struct {
+	jiraClient *jira.Client
+	resolve    adf.MentionResolver
+	fields     map[string]map[string]*FieldMeta
+	fieldErrs  map[string]error
+	users      map[string]*jira.User
+}
+
+func (v *bulkValidator) validate(row *bulkRow) (*jira.IssueFields, error) {
+	var missing []string
+	if row.Project == "" {
+		missing = append(missing, "project")
+	}
+	if row.IssueType == "" {
+		missing = append(missing, "type")
+	}
+	if row.Summary == "" {
+		missing = append(missing, "summary")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	fields, err := v.createMetaFields(row.Project, row.IssueType)
+	if err != nil {
+		return nil, err
+	}
+
+	custom := tcontainer.NewMarshalMap()
+	for name, value := range row.Fields {
+		field := findField(fields, name)
+		if field == nil {
+			return nil, fmt.Errorf("field %q is not available for %s in project %s", name, row.IssueType, row.Project)
+		}
+		if custom[field.Key], err = v.fieldValue(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	provided := map[string]bool{
+		"description": row.Description != "",
+		"labels":      len(row.Labels) > 0,
+		"components":  len(row.Components) > 0,
+		"priority":    row.Priority != "",
+		"assignee":    row.Assignee != "",
+		"parent":      row.Parent != "",
+	}
+	var missingFields []string
+	for key, field := range fields {
+		if !field.Required || field.HasDefaultValue || provided[key] || custom[key] != nil ||
+			utils.Contains(systemFields, key) {
+			continue
+		}
+		missingFields = append(missingFields, field.Name)
+	}
+	if len(missingFields) > 0 {
+		sort.Strings(missingFields)
+		return nil, fmt.Errorf("missing required fields: %s", strings.Join(missingFields, ", "))
+	}
+
+	if row.Description != "" {
+		custom["description"] = adf.FromMarkdown(row.Description, v.resolve)
+	}
+	if row.Assignee != "" {
+		u, err := v.findUser(row.Assignee)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve assignee: %w", err)
+		}
+		custom["assignee"] = map[string]string{"accountId": u.AccountID}
+	}
+
+	issueFields := &jira.IssueFields{
+		Summary:  row.Summary,
+		Labels:   row.Labels,
+		Project:  jira.Project{Key: row.Project},
+		Type:     jira.IssueType{Name: row.IssueType},
+		Unknowns: custom,
+	}
+	if row.Parent != "" {
+		issueFields.Parent = &jira.Parent{Key: row.Parent}
+	}
+	if row.Priority != "" {
+		issueFields.Priority = &jira.Priority{Name: row.Priority}
+	}
+	for _, name := range row.Components {
+		issueFields.Components = append(issueFields.Components, &jira.Component{Name: name})
+	}
+	return issueFields, nil
+}
+
+func (v *bulkValidator) createMetaFields(projectKey, issueTypeName string) (map[string]*FieldMeta, error) {
+	cacheKey := strings.ToUpper(projectKey) + "/" + strings.ToLower(issueTypeName)
+	if fields, ok := v.fields[cacheKey]; ok {
+		return fields, v.fieldErrs[cacheKey]
+	}
+
+	fields, err := getCreateMetaFields(v.jiraClient, projectKey, &jira.IssueType{Name: issueTypeName})
+	v.fields[cacheKey] = fields
+	v.fieldErrs[cacheKey] = err
+
+	return fields, err
+}
+
+// findUser looks every user up once per file, queries are case insensitive like user search
+func (v *bulkValidator) findUser(query string) (*jira.User, error) {
+	if u, ok := v.users[strings.ToLower(query)]; ok {
+		return u, nil
+	}
+
+	u, err := users.FindUser(v.jiraClient, query)
+	if err != nil {
+		return nil, err
+	}
+	v.users[strings.ToLower(query)] = u
+
+	return u, nil
+}
+
+// findField finds field by key or name
+func findField(fields map[string]*FieldMeta, name string) *FieldMeta {
+	if f, ok := fields[name]; ok {
+		return f
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// fieldValue converts plain text value, e.g. from csv, to a form expected by field.
+// Users are looked up by name, email or account id. Structured values are sent as they are.
+func (v *bulkValidator) fieldValue(field *FieldMeta, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	if isRichTextField(field) {
+		return adf.FromMarkdown(s, v.resolve), nil
+	}
+
+	isArray := field.Schema.Type == "array"
+	itemType := field.Schema.Type
+	if isArray {
+		itemType = field.Schema.Items
+	}
+
+	item := func(s string) (interface{}, error) {
+		switch itemType {
+		case "number":
+			n, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("field %q expects a number, got %q", field.Name, s)
+			}
+			return n, nil
+		case "option":
+			return map[string]string{"value": s}, nil
+		case "user":
+			u, err := v.findUser(s)
+			if err != nil {
+				return nil, fmt.Errorf("cannot resolve %s: %w", field.Name, err)
+			}
+			return map[string]string{"accountId": u.AccountID}, nil
+		case "string", "date", "datetime":
+			return s, nil
+		}
+		return map[string]string{"name": s}, nil
+	}
+
+	if !isArray {
+		return item(s)
+	}
+
+	var values []interface{}
+	for _, itemValue := range splitList(s) {
+		converted, err := item(itemValue)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, converted)
+	}
+
+	return values, nil
+}
+
+// createBulk creates valid rows in batches, several batches are sent concurrently
+func createBulk(jiraClient *jira.Client, rows []*bulkRow) {
+	var valid []*bulkRow
+	for _, row := range rows {
+		if row.err == nil {
+			valid = append(valid, row)
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bulkConcurrency)
+	for start := 0; start < len(valid); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []*bulkRow) {
+			defer wg.Done()
+			createBatch(jiraClient, batch)
+			<-sem
+		}(valid[start:end])
+	}
+
+	wg.Wait()
+}
+
+type bulkIssueUpdate struct {
+	Fields *jira.IssueFields `json:"fields"`
+}
+
+type bulkCreateResponse struct {
+	Issues []jira.Issue `json:"issues"`
+	Errors []struct {
+		Status        int `json:"status"`
+		ElementErrors struct {
+			ErrorMessages []string          `json:"errorMessages"`
+			Errors        map[string]string `json:"errors"`
+		} `json:"elementErrors"`
+		FailedElementNumber int `json:"failedElementNumber"`
+	} `json:"errors"`
+}
+
+// createBatch creates up to bulkBatchSize issues with a single request and
+// sets either created key or error on every row
+func createBatch(jiraClient *jira.Client, batch []*bulkRow) {
+	updates := make([]bulkIssueUpdate, 0, len(batch))
+	for _, row := range batch {
+		updates = append(updates, bulkIssueUpdate{Fields: row.fields})
+	}
+
+	setErr := func(err error) {
+		for _, row := range batch {
+			row.err = err
+		}
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost, "rest/api/3/issue/bulk",
+		map[string]interface{}{"issueUpdates": updates})
+	if err != nil {
+		setErr(err)
+		return
+	}
+
+	result := &bulkCreateResponse{}
+	resp, err := jiraClient.Do(req, result)
+	if err != nil {
+		if resp == nil {
+			setErr(err)
+			return
+		}
+		// jira responds with 400 when none of issues was created,
+		// errors are described in the same way as for partial success
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(body, result) != nil || len(result.Errors) == 0 {
+			setErr(errors.New(strings.TrimSpace(string(body))))
+			return
+		}
+	}
+
+	failed := make(map[int]bool)
+	for _, e := range result.Errors {
+		if e.FailedElementNumber < 0 || e.FailedElementNumber >= len(batch) {
+			continue
+		}
+		failed[e.FailedElementNumber] = true
+
+		messages := append([]string{}, e.ElementErrors.ErrorMessages...)
+		keys := make([]string, 0, len(e.ElementErrors.Errors))
+		for k := range e.ElementErrors.Errors {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			messages = append(messages, fmt.Sprintf("%s: %s", k, e.ElementErrors.Errors[k]))
+		}
+		batch[e.FailedElementNumber].err = errors.New(strings.Join(messages, "; "))
+	}
+
+	// created issues are listed in the order of successful elements
+	created := result.Issues
+	for i, row := range batch {
+		if failed[i] {
+			continue
+		}
+		if len(created) == 0 {
+			row.err = errors.New("issue was not created")
+			continue
+		}
+		row.key = created[0].Key
+		created = created[1:]
+	}
+}
+
+func printBulkResult(out io.Writer, jiraClient *jira.Client, rows []*bulkRow, dryRun bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tSUMMARY\tRESULT")
+	for i, row := range rows {
+		var result string
+		switch {
+		case row.err != nil:
+			result = "error: " + row.err.Error()
+		case dryRun:
+			result = "ok"
+		default:
+			result = fmt.Sprintf("%sbrowse/%s", jiraClient.BaseURL.String(), row.key)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, row.Summary, result)
+	}
+	w.Flush()
+}
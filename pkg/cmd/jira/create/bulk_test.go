@@ -0,0 +1,218 @@
+package create
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate_test_bulk(t *testing.T) {
+	severity := map[string]interface{}{
+		"key": "customfield_1", "required": true, "name": "Severity",
+		"schema": map[string]interface{}{"type": "option", "custom": "select"},
+	}
+
+	tests := []struct {
+		name       string
+		fileName   string
+		content    string
+		args       []string
+		httpStubs  func(*httpmock.Registry, *[]interface{})
+		expectOut  string
+		expectErr  string
+		expectBody string
+	}{
+		{
+			name:     "should validate yaml file in dry run",
+			fileName: "plan.yml",
+			content: heredoc.Doc(`
+				- summary: First
+				  labels: [a]
+				  fields:
+				    Severity: High
+				- summary: Second
+				- type: Bug
+			`),
+			args: []string{"--project", "PROJ", "--type", "Task", "--dry-run"},
+			httpStubs: func(r *httpmock.Registry, _ *[]interface{}) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(r, "PROJ", "10001", severity)
+			},
+			expectOut: heredoc.Doc(`
+				ROW  SUMMARY  RESULT
+				1    First    ok
+				2    Second   error: missing required fields: Severity
+				3             error: missing summary
+			`),
+			expectErr: "2 of 3 issues are invalid",
+		},
+		{
+			name:     "should create issues from csv and report failed rows",
+			fileName: "plan.csv",
+			content: heredoc.Doc(`
+				project,type,summary,labels,Severity
+				PROJ,Task,First,"a,b",High
+				PROJ,Task,Second,,Low
+			`),
+			httpStubs: func(r *httpmock.Registry, bodies *[]interface{}) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(r, "PROJ", "10001", severity)
+				r.Register(
+					httpmock.REST("POST", "rest/api/3/issue/bulk"),
+					func(req *http.Request) (*http.Response, error) {
+						var body interface{}
+						_ = json.NewDecoder(req.Body).Decode(&body)
+						*bodies = append(*bodies, body)
+						return httpmock.JSONResponse(map[string]interface{}{
+							"issues": []map[string]interface{}{{"key": "PROJ-1"}},
+							"errors": []map[string]interface{}{{
+								"status":              400,
+								"failedElementNumber": 1,
+								"elementErrors": map[string]interface{}{
+									"errors": map[string]string{"customfield_1": "Option is not valid"},
+								},
+							}},
+						})(req)
+					},
+				)
+			},
+			expectOut: heredoc.Doc(`
+				ROW  SUMMARY  RESULT
+				1    First    https://jira-url/browse/PROJ-1
+				2    Second   error: customfield_1: Option is not valid
+			`),
+			expectErr: "1 of 2 issues were not created",
+			expectBody: `{"issueUpdates":[
+				{"fields":{"summary":"First","labels":["a","b"],"project":{"key":"PROJ"},"issuetype":{"name":"Task"},"customfield_1":{"value":"High"}}},
+				{"fields":{"summary":"Second","project":{"key":"PROJ"},"issuetype":{"name":"Task"},"customfield_1":{"value":"Low"}}}]}`,
+		},
+		{
+			name:     "should look up users once per file",
+			fileName: "plan.csv",
+			content: heredoc.Doc(`
+				project,type,summary,Reviewer,assignee
+				PROJ,Task,First,jane,jane
+				PROJ,Task,Second,Jane,
+			`),
+			httpStubs: func(r *httpmock.Registry, bodies *[]interface{}) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(r, "PROJ", "10001", map[string]interface{}{
+					"key": "customfield_2", "name": "Reviewer", "schema": map[string]interface{}{"type": "user"},
+				})
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"jane"}}),
+					httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`),
+				)
+				r.Register(
+					httpmock.REST("POST", "rest/api/3/issue/bulk"),
+					func(req *http.Request) (*http.Response, error) {
+						var body interface{}
+						_ = json.NewDecoder(req.Body).Decode(&body)
+						*bodies = append(*bodies, body)
+						return httpmock.JSONResponse(map[string]interface{}{
+							"issues": []map[string]interface{}{{"key": "PROJ-1"}, {"key": "PROJ-2"}},
+						})(req)
+					},
+				)
+			},
+			expectOut: heredoc.Doc(`
+				ROW  SUMMARY  RESULT
+				1    First    https://jira-url/browse/PROJ-1
+				2    Second   https://jira-url/browse/PROJ-2
+			`),
+			expectBody: `{"issueUpdates":[
+				{"fields":{"summary":"First","project":{"key":"PROJ"},"issuetype":{"name":"Task"},"customfield_2":{"accountId":"42"},"assignee":{"accountId":"42"}}},
+				{"fields":{"summary":"Second","project":{"key":"PROJ"},"issuetype":{"name":"Task"},"customfield_2":{"accountId":"42"}}}]}`,
+		},
+		{
+			name:     "should create issues from json",
+			fileName: "plan.json",
+			content:  `[{"project": "PROJ", "type": "Task", "summary": "Only"}]`,
+			httpStubs: func(r *httpmock.Registry, _ *[]interface{}) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(r, "PROJ", "10001")
+				r.Register(
+					httpmock.REST("POST", "rest/api/3/issue/bulk"),
+					httpmock.JSONResponse(map[string]interface{}{
+						"issues": []map[string]interface{}{{"key": "PROJ-7"}},
+					}),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				ROW  SUMMARY  RESULT
+				1    Only     https://jira-url/browse/PROJ-7
+			`),
+		},
+		{
+			name:      "should reject unknown format",
+			fileName:  "plan.txt",
+			content:   "summary",
+			expectErr: `unsupported file format ".txt", use .csv, .yml, .yaml or .json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			var bodies []interface{}
+			if tt.httpStubs != nil {
+				tt.httpStubs(reg, &bodies)
+			}
+
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				// bulk creation never prompts
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			// when
+			err := runCreateCommand(factory, append([]string{"--from-file", path}, tt.args...)...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectOut, out.String())
+
+			if tt.expectBody != "" {
+				assert.Len(t, bodies, 1)
+				body, _ := json.Marshal(bodies[0])
+				assert.JSONEq(t, tt.expectBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+const (
+	cloneLinkType = "clones"
+	// sprintFieldType can't be copied, sprint is referenced by id on create
+	sprintFieldType = "com.pyxis.greenhopper.jira:gh-sprint"
+
+	doneEditing = "Done"
+	// noValue removes copied value of optional field
+	noValue = "None"
+)
+
+// clonedIssue holds values copied from issue given by --clone
+type clonedIssue struct {
+	key string
+	// fields are sent as they are, e.g. description in atlassian document format
+	fields tcontainer.MarshalMap
+	// copied holds keys of every copied field, including labels,
+	// components and priority, which are merged with flags
+	copied map[string]bool
+	// custom holds create screen fields of copied custom fields, they can be edited
+	custom map[string]*FieldMeta
+	// text describes values of custom fields in edit picker
+	text map[string]string
+}
+
+type sourceIssue struct {
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// loadClone fetches issue to clone and uses its values as defaults of new issue,
+// values given by flags are kept
+func loadClone(jiraClient *jira.Client, ops *CreateOptions) error {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet,
+		"rest/api/3/issue/"+url.PathEscape(ops.Clone), nil)
+	if err != nil {
+		return err
+	}
+
+	src := &sourceIssue{}
+	resp, err := jiraClient.Do(req, src)
+	if err != nil {
+		return fmt.Errorf("cannot fetch issue %s to clone: %w", ops.Clone, jira.NewJiraError(resp, err))
+	}
+
+	clone := &clonedIssue{
+		key:    src.Key,
+		fields: tcontainer.NewMarshalMap(),
+		copied: map[string]bool{},
+		custom: map[string]*FieldMeta{},
+		text:   map[string]string{},
+	}
+
+	summary, _ := src.Fields["summary"].(string)
+	ops.Summary = firstNonEmpty(ops.Summary, summary)
+	ops.ProjectKey = firstNonEmpty(ops.ProjectKey, nestedString(src.Fields, "project", "key"))
+	ops.IssueTypeName = firstNonEmpty(ops.IssueTypeName, nestedString(src.Fields, "issuetype", "name"))
+
+	if labels := names(src.Fields["labels"], ""); len(labels) > 0 {
+		ops.Labels = mergeValues(labels, ops.Labels)
+		clone.copied["labels"] = true
+	}
+	if components := names(src.Fields["components"], "name"); len(components) > 0 && len(ops.Components) == 0 {
+		ops.Components = components
+		clone.copied["components"] = true
+	}
+	if priority := nestedString(src.Fields, "priority", "name"); priority != "" && ops.Priority == "" {
+		ops.Priority = priority
+		clone.copied["priority"] = true
+	}
+
+	// custom fields are copied only when they can be set in target project and issue type
+	screen, err := getCreateMetaFields(jiraClient, ops.ProjectKey, &jira.IssueType{Name: ops.IssueTypeName})
+	if err != nil {
+		return fmt.Errorf("cannot get fields of %s in project %s: %w", ops.IssueTypeName, ops.ProjectKey, err)
+	}
+
+	keys := make([]string, 0, len(src.Fields))
+	for key := range src.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := src.Fields[key]
+		if key != "description" && key != "parent" && !strings.HasPrefix(key, "customfield_") {
+			continue
+		}
+		if isEmptyValue(value) {
+			continue
+		}
+		if strings.HasPrefix(key, "customfield_") {
+			field, ok := screen[key]
+			switch {
+			case !ok:
+				fmt.Fprintf(ops.Out, "warning: %s of %s is not copied, it is not on the create screen in %s %s\n",
+					key, src.Key, ops.ProjectKey, ops.IssueTypeName)
+				continue
+			case field.Schema.Custom == sprintFieldType:
+				fmt.Fprintf(ops.Out, "warning: %s of %s is not copied, use --sprint instead\n", field.Name, src.Key)
+				continue
+			}
+			clone.custom[key] = field
+			clone.text[key] = valueText(value)
+		}
+		clone.fields[key] = inputValue(value)
+		clone.copied[key] = true
+	}
+
+	ops.clone = clone
+	return nil
+}
+
+// editClone lets user change copied values before issue is created
+func editClone(jiraClient *jira.Client, ops *CreateOptions) error {
+	customKeys := make([]string, 0, len(ops.clone.custom))
+	for key := range ops.clone.custom {
+		customKeys = append(customKeys, key)
+	}
+	sort.Slice(customKeys, func(i, j int) bool {
+		return ops.clone.custom[customKeys[i]].Name < ops.clone.custom[customKeys[j]].Name
+	})
+
+	for {
+		options := []string{
+			doneEditing,
+			"Project: " + ops.ProjectKey,
+			"Type: " + ops.IssueTypeName,
+			"Summary: " + ops.Summary,
+			"Description",
+			"Labels: " + strings.Join(ops.Labels, ", "),
+			"Components: " + strings.Join(ops.Components, ", "),
+			"Priority: " + ops.Priority,
+		}
+		// custom fields are found by option, their names may contain ": "
+		custom := make(map[string]string, len(customKeys))
+		for _, key := range customKeys {
+			option := ops.clone.custom[key].Name + ": " + ops.clone.text[key]
+			custom[option] = key
+			options = append(options, option)
+		}
+
+		choice, err := ops.Prompter.Select(fmt.Sprintf("Edit values copied from %s", ops.clone.key), options)
+		if err != nil {
+			return err
+		}
+
+		if key, ok := custom[choice]; ok {
+			if err = editClonedField(jiraClient, ops, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, value, _ := strings.Cut(choice, ": ")
+		if name == doneEditing {
+			return nil
+		}
+		if name == "Description" {
+			if err = editClonedDescription(ops); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err = ops.Prompter.Input(name, value)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "Project":
+			ops.ProjectKey = value
+		case "Type":
+			ops.IssueTypeName = value
+		case "Summary":
+			ops.Summary = value
+		case "Labels":
+			ops.Labels = splitList(value)
+		case "Components":
+			ops.Components = splitList(value)
+		case "Priority":
+			ops.Priority = value
+		}
+	}
+}
+
+func editClonedDescription(ops *CreateOptions) error {
+	description := ops.Description
+	if description == "" {
+		if doc, err := toDocument(ops.clone.fields["description"]); err == nil {
+			description = adf.ToMarkdown(doc)
+		}
+	}
+
+	text, err := ops.Prompter.Editor("Issue Description", description+adf.EditorHint)
+	if err != nil {
+		return err
+	}
+
+	// edited description is sent as markdown converted by newIssue
+	ops.Description = adf.StripComments(text)
+	delete(ops.clone.fields, "description")
+	return nil
+}
+
+// editClonedField lets user change value of copied custom field, allowed values are picked,
+// other values are typed like values of bulk rows. None or empty value removes the field.
+func editClonedField(jiraClient *jira.Client, ops *CreateOptions, key string) error {
+	field := ops.clone.custom[key]
+
+	var value interface{}
+	var text string
+	var err error
+	switch {
+	case len(field.AllowedValues) > 0:
+		labels := make([]string, 0, len(field.AllowedValues))
+		for _, v := range field.AllowedValues {
+			labels = append(labels, v.Label())
+		}
+		if !field.Required {
+			labels = append(labels, noValue)
+		}
+		if text, err = ops.Prompter.Select(field.Name, labels); err != nil {
+			return err
+		}
+		for _, v := range field.AllowedValues {
+			if v.Label() == text {
+				value = map[string]interface{}{"id": v.ID}
+			}
+		}
+		switch {
+		case value == nil:
+			text = ""
+		case field.Schema.Type == "array":
+			value = []interface{}{value}
+		}
+	case isRichTextField(field):
+		description := ""
+		if doc, err := toDocument(ops.clone.fields[key]); err == nil {
+			description = adf.ToMarkdown(doc)
+		}
+		if text, err = ops.Prompter.Editor(field.Name, description); err != nil {
+			return err
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			value = adf.FromMarkdown(text, users.NewMentionResolver(jiraClient))
+			text = firstLine(text)
+		}
+	default:
+		if text, err = ops.Prompter.Input(field.Name, ops.clone.text[key]); err != nil {
+			return err
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			v := &bulkValidator{
+				jiraClient: jiraClient,
+				resolve:    users.NewMentionResolver(jiraClient),
+				users:      make(map[string]*jira.User),
+			}
+			if value, err = v.fieldValue(field, text); err != nil {
+				return err
+			}
+		}
+	}
+
+	ops.clone.text[key] = text
+	if value == nil {
+		delete(ops.clone.fields, key)
+		delete(ops.clone.copied, key)
+		return nil
+	}
+	ops.clone.fields[key] = value
+	ops.clone.copied[key] = true
+	return nil
+}
+
+// dropInvalidClonedFields removes copied fields, which can't be set in target
+// project and issue type, e.g. custom fields missing on its create screen
+func dropInvalidClonedFields(ops *CreateOptions, issue *jira.Issue, screen map[string]*FieldMeta) error {
+	if ops.clone == nil || screen == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(issue.Fields)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ops.clone.copied))
+	for k := range ops.clone.copied {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			// replaced by flags or by edit
+			continue
+		}
+
+		name, reason := key, ""
+		field, ok := screen[key]
+		switch {
+		case !ok:
+			reason = "it is not on the create screen"
+		case field.Schema.Custom == sprintFieldType:
+			name, reason = field.Name, "use --sprint instead"
+		default:
+			name = field.Name
+			for _, v := range referencedValues(value) {
+				if len(field.AllowedValues) > 0 && !isAllowed(field, v) {
+					reason = fmt.Sprintf("%q is not allowed", v)
+					break
+				}
+			}
+		}
+		if reason == "" {
+			continue
+		}
+
+		switch key {
+		case "labels":
+			issue.Fields.Labels = nil
+		case "components":
+			issue.Fields.Components = nil
+		case "priority":
+			issue.Fields.Priority = nil
+		default:
+			delete(issue.Fields.Unknowns, key)
+		}
+		fmt.Fprintf(ops.Out, "warning: %s of %s is not copied, %s in %s %s\n",
+			name, ops.clone.key, reason, issue.Fields.Project.Key, issue.Fields.Type.Name)
+	}
+
+	return nil
+}
+
+// inputValue reduces value read from jira to a form accepted on create,
+// referenced objects like options or users are sent by their id only
+func inputValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, inputValue(item))
+		}
+		return result
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			return v
+		}
+		for _, k := range []string{"accountId", "id", "key", "value", "name"} {
+			if ref, ok := v[k]; ok {
+				result := map[string]interface{}{k: ref}
+				if child, ok := v["child"]; ok {
+					// cascading select
+					result["child"] = inputValue(child)
+				}
+				return result
+			}
+		}
+	}
+
+	return value
+}
+
+func toDocument(value interface{}) (*adf.Node, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	doc := &adf.Node{}
+	return doc, json.Unmarshal(data, doc)
+}
+
+// valueText describes value read from jira, e.g. option by its value and user by name
+func valueText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		texts := make([]string, 0, len(v))
+		for _, item := range v {
+			texts = append(texts, valueText(item))
+		}
+		return strings.Join(texts, ", ")
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			if doc, err := toDocument(v); err == nil {
+				return firstLine(adf.ToMarkdown(doc))
+			}
+		}
+		for _, k := range []string{"value", "name", "displayName", "key", "id", "accountId"} {
+			if s, ok := v[k].(string); ok {
+				return s
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func nestedString(fields map[string]interface{}, key, name string) string {
+	m, _ := fields[key].(map[string]interface{})
+	s, _ := m[name].(string)
+	return s
+}
+
+// names returns strings of list value, or given attribute of its objects
+func names(value interface{}, attr string) []string {
+	list, _ := value.([]interface{})
+	var result []string
+	for _, item := range list {
+		if attr != "" {
+			m, _ := item.(map[string]interface{})
+			item = m[attr]
+		}
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -2,29 +2,120 @@ package create
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"os"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/adf"
 	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
 	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
 	"github.com/stirboy/jh/pkg/cmd/jira/users"
 	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/export"
 	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
 	"github.com/stirboy/jh/pkg/utils"
+	"github.com/trivago/tgo/tcontainer"
 )
 
+// outputTemplateFlag is go template flag of exported issue, --template selects issue template
+const outputTemplateFlag = "output-template"
+
 type CreateOptions struct {
 	Config          func() (config.Config, error)
 	JiraClient      func() (*jira.Client, error)
 	Prompter        prompt.Prompter
 	GitClient       func() (gitclient.GitClient, error)
+	IOStream        *iostreams.IOStream
+	Browser         func(string) error
 	CreateGitBranch string
-	IsInteractive   bool
-	Out             io.Writer
+	// Checkout creates branch named by configured branch template
+	Checkout      bool
+	IsInteractive bool
+	Out           io.Writer
+
+	Summary       string
+	ProjectKey    string
+	IssueTypeName string
+	Description   string
+	// DescriptionFile is a path to markdown file with description, "-" means stdin
+	DescriptionFile string
+	UseEditor       bool
+	Labels          []string
+	Components      []string
+	Priority        string
+	Assignee        string
+	Reporter        string
+	// Parent is a key of parent issue, it makes the new issue a sub-task
+	Parent  string
+	SubTask bool
+	// Epic is a key of epic the new issue is added to
+	Epic string
+	// Sprint is "active", "next" or name of sprint the new issue is moved into
+	Sprint string
+	// Blocks, BlockedBy and RelatesTo are keys of issues the new issue is linked to
+	Blocks    []string
+	BlockedBy []string
+	RelatesTo []string
+	// Attach is a list of files uploaded to the new issue
+	Attach []string
+
+	// FromCommit is a revision of commit summary and description are taken from
+	FromCommit string
+	// FromBranch takes summary and description from current branch
+	FromBranch bool
+
+	// CheckDuplicates searches for similar open issues before issue is created,
+	// when not set by flag, it depends on configuration and terminal
+	CheckDuplicates    bool
+	CheckDuplicatesSet bool
+
+	// IssueFlagsSet tells that some issue values were given by flags
+	IssueFlagsSet bool
+
+	// Clone is a key of issue new issue is copied from
+	Clone string
+	clone *clonedIssue
+
+	TemplateName string
+	TemplateVars map[string]string
+
+	// FromFile is a path to csv, yaml or json file with issues to create in bulk
+	FromFile string
+	DryRun   bool
+
+	// Exporter prints created issue as json, or formats it by jq expression or go template
+	Exporter export.Options
+
+	// failures are errors of steps done after issue was created, e.g. failed link,
+	// remaining steps are done anyway and failures are reported together at the end
+	failures []error
+}
+
+// outputFlags change how issue is created or printed, not issue itself
+var outputFlags = map[string]bool{
+	"interactive": true, "branch": true, "checkout": true, "dry-run": true,
+	"check-duplicates": true, "json": true, "jq": true, outputTemplateFlag: true,
+}
+
+// issueFlagsSet reports whether any flag setting values of new issue was given
+func issueFlagsSet(cmd *cobra.Command) bool {
+	n := cmd.Flags().NFlag()
+	for name := range outputFlags {
+		if cmd.Flags().Changed(name) {
+			n--
+		}
+	}
+	return n > 0
 }
 
 func NewCreateCmd(f *factory.Factory) *cobra.Command {
@@ -33,6 +124,8 @@ func NewCreateCmd(f *factory.Factory) *cobra.Command {
 		JiraClient: f.JiraClient,
 		Prompter:   f.Prompter,
 		GitClient:  f.GitClient,
+		IOStream:   f.IOStream,
+		Browser:    f.Browser,
 		Out:        f.IOStream.Out,
 	}
 
@@ -40,6 +133,12 @@ func NewCreateCmd(f *factory.Factory) *cobra.Command {
 		Use:     "create",
 		Aliases: []string{"cr"},
 		Short:   "Create jira issue",
+		Long: heredoc.Doc(`
+			Create jira issue.
+
+			Unlike other commands, go template of --json output is given with --output-template,
+			because --template selects issue template. --json and --jq work as in other commands.
+		`),
 		Example: heredoc.Doc(`
 			# create jira issue
 			$ jh create
@@ -57,47 +156,302 @@ func NewCreateCmd(f *factory.Factory) *cobra.Command {
 			# @ sign is replaced with actual jira issue key
 			# Ex. feature/@/test --> feature/issue-1/test
 			$ jh create -b @/branch-name
+
+			# branch name can be built from issue values: {key} (lowercased), {KEY}, {type},
+			# {summary} and {user}, characters which are not allowed in git are replaced
+			# Ex. {type}/{KEY}-{summary} --> task/PROJ-1-fix-login-page
+			$ jh create -b "{type}/{KEY}-{summary}"
+
+			# create branch named by configuration.git.branchTemplate (default is {type}/{key}-{summary})
+			$ jh config set configuration.git.branchTemplate "{user}/{key}"
+			$ jh create -c
+
+			# create jira issue without any prompts (e.g. in CI or scripts)
+			$ jh create --project PROJ --type Task --summary "Bump dependencies" --label deps
+
+			# description is written in markdown and can be read from file, stdin or $EDITOR
+			$ jh create --description-file notes.md
+			$ git log -1 --format=%b | jh create -s "Fix login" --description-file -
+			$ jh create --editor
+
+			# create sub-task of given issue, or pick parent among open issues of the project
+			$ jh create --parent PROJ-12
+			$ jh create --subtask
+
+			# add new issue to epic and to active sprint of the project board,
+			# board is remembered in configuration.boards.<project key>
+			$ jh create --epic PROJ-7 --sprint active
+			$ jh create --sprint next
+			$ jh create --sprint "Sprint 42"
+
+			# link new issue to other issues
+			$ jh create --blocks PROJ-3 --blocked-by PROJ-4 --relates-to PROJ-5,PROJ-6
+
+			# attach files to new issue
+			$ jh create --type Bug --attach crash.log --attach screenshot.png
+
+			# take summary and description from commit message and changed files
+			$ jh create --from-commit
+			$ jh create --from-commit HEAD~2
+
+			# take summary from current branch name and description from its commits
+			# and changed files, branch is compared with configuration.git.baseBranch,
+			# origin/HEAD, main or master
+			$ jh create --from-branch
+
+			# copy issue, e.g. to repeat it for another platform, copied values can be
+			# changed before issue is created and new issue is linked to the original
+			$ jh create --clone PROJ-12
+			$ jh create --clone PROJ-12 --project IOS -s "Login fails on iOS"
+
+			# similar open issues are shown before issue is created in terminal,
+			# the check can be turned off per project or forced in scripts
+			$ jh config set configuration.checkDuplicates.PROJ false
+			$ jh create -p PROJ -t Bug -s "Login fails" --check-duplicates
+
+			# create jira issue from template defined in config.yml (configuration.templates.bug)
+			# or in .jh/templates/bug.yml of current repository
+			$ jh create --template bug --var version=1.2.0
+
+			# print issue which would be created and validate it
+			$ jh create --template bug --dry-run
+
+			# print created issue as json, or its values by jq expression or go template,
+			# --output-template is used as --template selects issue template
+			$ jh create -p PROJ -t Task -s "Bump dependencies" --json=key,url
+			$ jh create -p PROJ -t Task -s "Bump dependencies" --jq .key
+			$ jh create -p PROJ -t Task -s "Bump dependencies" --output-template '{{.key}} {{.url}}'
+
+			# create many issues at once, rows miss project or type use --project, --type or stored defaults
+			$ jh create --from-file plan.yml --dry-run
+			$ jh create --from-file plan.csv --project PROJ
 		`),
 
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				// --from-commit has optional value, so revision can be given as a separate argument
+				if ops.FromCommit != headRevision {
+					return fmt.Errorf("unexpected argument %q", args[0])
+				}
+				ops.FromCommit = args[0]
+			}
+			ops.CheckDuplicatesSet = cmd.Flags().Changed("check-duplicates")
+			ops.IssueFlagsSet = issueFlagsSet(cmd)
 			return run(ops)
 		},
 	}
 
-	cmd.Flags().StringVarP(&ops.CreateGitBranch, "branch", "b", "", "Create new branch with newly created jira issue key")
+	cmd.Flags().StringVarP(&ops.CreateGitBranch, "branch", "b", "", "Create new branch named by template, e.g. feature/{key}-{summary}")
+	cmd.Flags().BoolVarP(&ops.Checkout, "checkout", "c", false, "Create new branch named by configuration.git.branchTemplate")
+	cmd.MarkFlagsMutuallyExclusive("branch", "checkout")
 	cmd.Flags().BoolVarP(&ops.IsInteractive, "interactive", "i", false, "Provide jira details interactively")
+	cmd.Flags().StringVarP(&ops.Summary, "summary", "s", "", "Issue summary")
+	cmd.Flags().StringVarP(&ops.ProjectKey, "project", "p", "", "Project key")
+	cmd.Flags().StringVarP(&ops.IssueTypeName, "type", "t", "", "Issue type name")
+	cmd.Flags().StringVarP(&ops.Description, "description", "d", "", "Issue description in markdown")
+	cmd.Flags().StringVar(&ops.DescriptionFile, "description-file", "", "Read markdown description from file (use \"-\" to read from stdin)")
+	cmd.Flags().BoolVarP(&ops.UseEditor, "editor", "e", false, "Write description in $VISUAL or $EDITOR")
+	cmd.MarkFlagsMutuallyExclusive("description", "description-file", "editor")
+	cmd.Flags().StringVar(&ops.TemplateName, "template", "", "Preset issue values from named template (use --output-template to format output)")
+	cmd.Flags().StringToStringVar(&ops.TemplateVars, "var", nil, "Fill template placeholder (name=value, can be repeated)")
+	cmd.Flags().StringVar(&ops.FromFile, "from-file", "", "Create issues listed in csv, yaml or json file")
+	cmd.Flags().BoolVar(&ops.DryRun, "dry-run", false, "Print and validate issues without creating them")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "branch")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "checkout")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "template")
+	cmd.Flags().StringSliceVarP(&ops.Labels, "label", "l", nil, "Add label (can be repeated)")
+	cmd.Flags().StringVar(&ops.Priority, "priority", "", "Issue priority name")
+	cmd.Flags().StringVar(&ops.Assignee, "assignee", "", "Assign issue to user (account id, email, name or @me)")
+	cmd.Flags().StringVar(&ops.Reporter, "reporter", "", "Set issue reporter (account id, email, name or @me)")
+	cmd.Flags().StringVar(&ops.Parent, "parent", "", "Create sub-task of issue with given key")
+	cmd.Flags().BoolVar(&ops.SubTask, "subtask", false, "Create sub-task, parent is picked interactively unless --parent is given")
+	cmd.Flags().StringVar(&ops.Epic, "epic", "", "Add issue to epic with given key")
+	cmd.Flags().StringVar(&ops.Sprint, "sprint", "", "Move issue into sprint: active, next or sprint name")
+	cmd.Flags().StringSliceVar(&ops.Blocks, "blocks", nil, "Link issue as blocking given issue (can be repeated)")
+	cmd.Flags().StringSliceVar(&ops.BlockedBy, "blocked-by", nil, "Link issue as blocked by given issue (can be repeated)")
+	cmd.Flags().StringSliceVar(&ops.RelatesTo, "relates-to", nil, "Link issue as related to given issue (can be repeated)")
+	cmd.Flags().StringVar(&ops.FromCommit, "from-commit", "", "Take summary and description from commit (default HEAD)")
+	cmd.Flags().Lookup("from-commit").NoOptDefVal = headRevision
+	cmd.Flags().BoolVar(&ops.FromBranch, "from-branch", false, "Take summary and description from current branch")
+	cmd.MarkFlagsMutuallyExclusive("from-commit", "from-branch")
+	cmd.Flags().StringVar(&ops.Clone, "clone", "", "Copy values of issue with given key and link new issue to it")
+	cmd.MarkFlagsMutuallyExclusive("clone", "from-commit")
+	cmd.MarkFlagsMutuallyExclusive("clone", "from-branch")
+	cmd.Flags().BoolVar(&ops.CheckDuplicates, "check-duplicates", false, "Search for similar open issues before creating (default true in terminal)")
+	cmd.Flags().StringArrayVar(&ops.Attach, "attach", nil, "Attach file to issue (can be repeated)")
+	// sub-tasks always belong to epic and sprint of their parent
+	cmd.MarkFlagsMutuallyExclusive("epic", "parent")
+	cmd.MarkFlagsMutuallyExclusive("epic", "subtask")
+	cmd.MarkFlagsMutuallyExclusive("sprint", "parent")
+	cmd.MarkFlagsMutuallyExclusive("sprint", "subtask")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "epic")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "sprint")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "blocks")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "blocked-by")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "relates-to")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "attach")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "from-commit")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "from-branch")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "check-duplicates")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "clone")
+	export.AddFlags(cmd, &ops.Exporter, outputTemplateFlag)
+	for _, name := range []string{"json", "jq", outputTemplateFlag} {
+		cmd.MarkFlagsMutuallyExclusive("from-file", name)
+		cmd.MarkFlagsMutuallyExclusive("dry-run", name)
+	}
 
 	return cmd
 }
 
 func run(ops *CreateOptions) error {
+	if ops.FromFile != "" {
+		return runBulk(ops)
+	}
+
+	if err := checkAttachments(ops.Attach); err != nil {
+		return err
+	}
+
+	if ops.Exporter.Enabled() {
+		// only exported issue is printed, so that output can be parsed
+		ops.Out = io.Discard
+	}
+
 	jiraClient, err := ops.JiraClient()
 	if err != nil {
 		return err
 	}
 	// create jira issue
 	issue, err := createJiraIssue(ops)
+	if errors.Is(err, errExistingIssueOpened) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	template, err := branchTemplate(ops)
 	if err != nil {
 		return err
 	}
 
+	if ops.DryRun {
+		if template != "" {
+			// issue key is not known until issue is created
+			preview := *issue
+			preview.Key = issue.Fields.Project.Key + "-NEW"
+			branchName, err := newBranchName(jiraClient, template, &preview)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(ops.Out, "\nbranch: %s\n", branchName)
+		}
+		if len(ops.Attach) > 0 {
+			fmt.Fprintln(ops.Out, "\nattachments:")
+			for _, path := range ops.Attach {
+				fmt.Fprintf(ops.Out, "  %s\n", path)
+			}
+		}
+		fmt.Fprintln(ops.Out, "\ndry run: issue was not created")
+		return nil
+	}
+
 	fmt.Fprintf(ops.Out, "\ncreated issue: %s%s%s\n", jiraClient.BaseURL, "browse/", issue.Key)
+	for _, link := range issue.Fields.IssueLinks {
+		fmt.Fprintf(ops.Out, "  %s\n", issues.DescribeLink(link))
+	}
+
+	ops.failures = append(ops.failures, uploadAttachments(jiraClient, ops, issue.Key)...)
 
 	// create and checkout to new branch
-	if ops.CreateGitBranch != "" {
-		branchName := strings.Replace(ops.CreateGitBranch, "@", strings.ToLower(issue.Key), 1)
+	if template != "" {
+		if err = createBranch(jiraClient, ops, template, issue); err != nil {
+			ops.failures = append(ops.failures, err)
+		}
+	}
 
-		gitClient, err := ops.GitClient()
+	if ops.Exporter.Enabled() {
+		data, err := exportedIssue(jiraClient, issue.Key, ops.Exporter.Fields)
 		if err != nil {
+			ops.failures = append(ops.failures, err)
+		} else if err = ops.Exporter.Write(ops.IOStream, data); err != nil {
 			return err
 		}
+	}
 
-		if err = gitClient.CreateBranchWithCheckout(branchName); err != nil {
-			return err
+	return partialFailure(jiraClient, issue.Key, ops.failures)
+}
+
+func createBranch(jiraClient *jira.Client, ops *CreateOptions, template string, issue *jira.Issue) error {
+	branchName, err := newBranchName(jiraClient, template, issue)
+	if err != nil {
+		return fmt.Errorf("cannot name branch: %w", err)
+	}
+
+	gitClient, err := ops.GitClient()
+	if err != nil {
+		return err
+	}
+
+	return gitClient.CreateBranchWithCheckout(branchName)
+}
+
+// partialFailure reports steps which failed after issue was created, so that
+// they can be finished by hand
+func partialFailure(jiraClient *jira.Client, issueKey string, failures []error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("issue %s%s%s was created, but:", jiraClient.BaseURL, "browse/", issueKey)
+	for _, err := range failures {
+		msg += "\n  " + err.Error()
+	}
+	return errors.New(msg)
+}
+
+// exportedIssue returns created issue as jh get exports it, jira responds to creation
+// with issue key only, so the issue is fetched to export fields set by jira, e.g. status
+func exportedIssue(jiraClient *jira.Client, key string, fields []string) (map[string]interface{}, error) {
+	query := url.Values{}
+	if jiraFields := issues.JiraFields(fields); len(jiraFields) > 0 {
+		query.Set("fields", strings.Join(jiraFields, ","))
+	}
+
+	raw, err := issues.GetRaw(jiraClient, key, query)
+	if err != nil {
+		return nil, err
+	}
+	return issues.ExportedIssue(raw, jiraClient.BaseURL.String())
+}
+
+// branchTemplate returns template of branch to create, empty when no branch is requested
+func branchTemplate(ops *CreateOptions) (string, error) {
+	if ops.CreateGitBranch != "" || !ops.Checkout {
+		return ops.CreateGitBranch, nil
+	}
+
+	cfg, err := ops.Config()
+	if err != nil {
+		return "", err
+	}
+
+	template, _ := cfg.GetNested([]string{"configuration", "git", "branchTemplate"})
+	return firstNonEmpty(template, defaultBranchTemplate), nil
+}
+
+func newBranchName(jiraClient *jira.Client, template string, issue *jira.Issue) (string, error) {
+	var user *jira.User
+	if strings.Contains(template, "{user}") {
+		var err error
+		user, _, err = users.GetCurrentUser(jiraClient)
+		if err != nil {
+			return "", err
 		}
 	}
 
-	return nil
+	return renderBranchName(template, newBranchValues(issue, user))
 }
 
 func createJiraIssue(ops *CreateOptions) (*jira.Issue, error) {
@@ -111,24 +465,64 @@ func createJiraIssue(ops *CreateOptions) (*jira.Issue, error) {
 		return nil, err
 	}
 
-	isConfigPopulated := true
-	projectKeyValue, err := cfg.GetNested([]string{"configuration", "issue", "projectKey"})
-	if err != nil || projectKeyValue == "" {
-		isConfigPopulated = false
+	projectKeyValue, _ := cfg.GetNested([]string{"configuration", "issue", "projectKey"})
+	issueTypeNameValue, _ := cfg.GetNested([]string{"configuration", "issue", "issueTypeName"})
+
+	if err = prefillFromGit(ops, cfg); err != nil {
+		return nil, err
+	}
+
+	if ops.Clone != "" {
+		if err = loadClone(jiraClient, ops); err != nil {
+			return nil, err
+		}
+		if ops.IOStream.CanPrompt() {
+			if err = editClone(jiraClient, ops); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if ops.TemplateName != "" {
+		t, err := loadTemplate(ops, cfg, ops.TemplateName)
+		if err != nil {
+			return nil, err
+		}
+		if err = applyTemplate(ops, t); err != nil {
+			return nil, err
+		}
+	}
+
+	ops.Labels = mergeValues(defaultLabels(cfg), ops.Labels)
+
+	if !ops.IOStream.CanPrompt() {
+		if err := checkRequiredValues(ops, projectKeyValue, issueTypeNameValue); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := readDescription(ops); err != nil {
+		return nil, err
 	}
 
-	if ops.IsInteractive || !isConfigPopulated {
-		if projectKeyValue == "" {
+	projectKey := firstNonEmpty(ops.ProjectKey, projectKeyValue)
+	issueTypeName := ops.IssueTypeName
+	if !isSubtaskRequested(ops) {
+		issueTypeName = firstNonEmpty(issueTypeName, issueTypeNameValue)
+	}
+
+	if ops.IsInteractive || projectKey == "" || issueTypeName == "" {
+		if projectKeyValue == "" && !ops.IssueFlagsSet {
 			fmt.Fprintln(ops.Out, "Looks like non-interactive mode was not configured. Running interactively...")
 		}
-		issue, err := runInteractive(jiraClient, cfg, ops.Prompter, ops.Out)
+		issue, err := runInteractive(jiraClient, cfg, ops)
 		if err != nil {
 			return nil, err
 		}
 
 		return issue, nil
 	} else {
-		issue, err := runNonInteractive(jiraClient, cfg, ops.Prompter)
+		issue, err := runNonInteractive(jiraClient, ops, projectKey, issueTypeName)
 		if err != nil {
 			return nil, err
 		}
@@ -137,69 +531,120 @@ func createJiraIssue(ops *CreateOptions) (*jira.Issue, error) {
 	}
 }
 
-func runNonInteractive(jiraClient *jira.Client, cfg config.Config, prompter prompt.Prompter) (*jira.Issue, error) {
-	// get current user without blocking the flow
-	curUserChan := make(chan *users.CurrentUserResult)
-	users.GetCurrentUserResultAsync(jiraClient, curUserChan)
+// checkRequiredValues makes sure that every value which would otherwise
+// be prompted for was provided, so that jh never waits for input
+// which can't be given.
+func checkRequiredValues(ops *CreateOptions, projectKeyValue, issueTypeNameValue string) error {
+	if ops.IsInteractive {
+		// stored defaults are ignored in interactive mode
+		projectKeyValue, issueTypeNameValue = "", ""
+	}
 
-	projectKey, err := cfg.GetNested([]string{"configuration", "issue", "projectKey"})
-	if err != nil {
-		return nil, err
+	var missing []string
+	if ops.Summary == "" {
+		missing = append(missing, "--summary")
+	}
+	if ops.ProjectKey == "" && projectKeyValue == "" {
+		missing = append(missing, "--project")
+	}
+	if ops.IssueTypeName == "" && (issueTypeNameValue == "" || isSubtaskRequested(ops)) {
+		// stored issue type is not a sub-task type
+		missing = append(missing, "--type")
+	}
+	if ops.SubTask && ops.Parent == "" {
+		missing = append(missing, "--parent")
 	}
 
-	issueTypeName, err := cfg.GetNested([]string{"configuration", "issue", "issueTypeName"})
-	if err != nil {
-		return nil, err
+	if len(missing) > 0 {
+		return fmt.Errorf("stdin is not a terminal, so jh cannot prompt for input. Missing required values: %s",
+			strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func runNonInteractive(jiraClient *jira.Client, ops *CreateOptions, projectKey, issueTypeName string) (*jira.Issue, error) {
+	issueType := &jira.IssueType{Name: issueTypeName}
+	if isSubtaskRequested(ops) {
+		issueTypes, err := getCreateMetaIssueTypes(jiraClient, projectKey)
+		if err != nil {
+			return nil, err
+		}
+		project := &Project{Key: projectKey, IssueTypes: issueTypes}
+		if issueType, err = findIssueType(project, issueTypeName); err != nil {
+			return nil, err
+		}
+		if err = checkSubtaskType(project, issueType); err != nil {
+			return nil, err
+		}
+		issueTypeName = issueType.Name
 	}
 
-	// get required fields for jira project without blocking the flow
+	// get current user without blocking the flow
+	curUserChan := make(chan *users.CurrentUserResult)
+	users.GetCurrentUserResultAsync(jiraClient, curUserChan)
+
+	// get required fields for jira project and issue type without blocking the flow
 	requiredFieldsChan := make(chan *RequiredFieldsResult)
-	getRequiredFieldsResultAsync(jiraClient, &Project{Key: projectKey}, requiredFieldsChan)
+	getRequiredFieldsResultAsync(jiraClient, projectKey, issueType, requiredFieldsChan)
 
-	summary, err := inputSummary(prompter)
-	if err != nil {
-		return nil, err
+	summary := ops.Summary
+	if summary == "" {
+		var err error
+		summary, err = inputSummary(ops.Prompter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parentKey := ops.Parent
+	if ops.SubTask && parentKey == "" {
+		var err error
+		parentKey, err = pickParent(jiraClient, ops.Prompter, projectKey)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// waiting fir current user to load
 	currentUserResult := <-curUserChan
-	if err = currentUserResult.Err; err != nil {
+	if err := currentUserResult.Err; err != nil {
 		return nil, err
 	}
 
 	// waiting for required fields to load
 	requiredFieldsResult := <-requiredFieldsChan
-	if err = requiredFieldsResult.err; err != nil {
+	if err := requiredFieldsResult.err; err != nil {
 		return nil, err
 	}
 
-	var reporter *jira.User
-	reporterRequired := requiredFieldsResult.fields["reporter"]
-	if reporterRequired {
-		reporter = currentUserResult.User
+	customFields, err := inputRequiredFields(jiraClient, ops, requiredFieldsResult.fields)
+	if err != nil {
+		return nil, err
 	}
 
-	issue, resp, err := jiraClient.Issue.Create(context.Background(), &jira.Issue{
-		Fields: &jira.IssueFields{
-			Summary:  summary,
-			Reporter: reporter,
-			Assignee: currentUserResult.User,
-			Project: jira.Project{
-				Key: projectKey,
-			},
-			Type: jira.IssueType{
-				Name: issueTypeName,
-			},
-		},
+	issue, err := newIssue(jiraClient, ops, &issueInput{
+		summary:          summary,
+		projectKey:       projectKey,
+		issueTypeName:    issueTypeName,
+		parentKey:        parentKey,
+		currentUser:      currentUserResult.User,
+		reporterRequired: requiredFieldsResult.fields["reporter"] != nil,
+		customFields:     customFields,
 	})
 	if err != nil {
-		return nil, utils.ParseJiraResponse(resp)
+		return nil, err
+	}
+
+	issue, err = submitIssue(jiraClient, ops, issue, requiredFieldsResult.allFields)
+	if err != nil {
+		return nil, err
 	}
 
 	return issue, nil
 }
 
-func runInteractive(jiraClient *jira.Client, cfg config.Config, prompter prompt.Prompter, out io.Writer) (*jira.Issue, error) {
+func runInteractive(jiraClient *jira.Client, cfg config.Config, ops *CreateOptions) (*jira.Issue, error) {
 	// get current user without blocking the flow
 	curUserChan := make(chan *users.CurrentUserResult)
 	users.GetCurrentUserResultAsync(jiraClient, curUserChan)
@@ -208,33 +653,55 @@ func runInteractive(jiraClient *jira.Client, cfg config.Config, prompter prompt.
 	recentProjectsResultChan := make(chan *ProjectResult)
 	getRecentProjectsResultAsync(jiraClient, recentProjectsResultChan)
 
-	summary, err := inputSummary(prompter)
-	if err != nil {
-		return nil, err
+	summary := ops.Summary
+	if summary == "" {
+		var err error
+		summary, err = inputSummary(ops.Prompter)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// waiting for recent project to load
 	recentProjectResult := <-recentProjectsResultChan
-	if err = recentProjectResult.err; err != nil {
+	if err := recentProjectResult.err; err != nil {
 		return nil, err
 	}
 
-	project, err := selectProject(prompter, recentProjectResult.projectKeyMap)
+	var project *Project
+	var err error
+	if ops.ProjectKey != "" {
+		project, err = findProject(jiraClient, recentProjectResult.projectKeyMap, ops.ProjectKey)
+	} else {
+		project, err = selectProject(ops.Prompter, recentProjectResult.projectKeyMap)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// get required fields for jira project without blocking the flow
-	requiredFieldsChan := make(chan *RequiredFieldsResult)
-	getRequiredFieldsResultAsync(jiraClient, project, requiredFieldsChan)
+	parentKey := ops.Parent
+	if ops.SubTask && parentKey == "" {
+		parentKey, err = pickParent(jiraClient, ops.Prompter, project.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	issueType, err := selectIssueType(prompter, project)
+	var issueType *jira.IssueType
+	if ops.IssueTypeName != "" {
+		issueType, err = findIssueType(project, ops.IssueTypeName)
+		if err == nil && parentKey != "" {
+			err = checkSubtaskType(project, issueType)
+		}
+	} else {
+		issueType, err = selectIssueType(ops.Prompter, project, parentKey != "")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// waiting for required fields to load
-	requiredFieldsResult := <-requiredFieldsChan
+	// required fields depend on selected issue type, nothing is left to do while they load
+	requiredFieldsResult := getRequiredFieldsResult(jiraClient, project.Key, issueType)
 	if err = requiredFieldsResult.err; err != nil {
 		return nil, err
 	}
@@ -245,38 +712,290 @@ func runInteractive(jiraClient *jira.Client, cfg config.Config, prompter prompt.
 		return nil, err
 	}
 
+	customFields, err := inputRequiredFields(jiraClient, ops, requiredFieldsResult.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := newIssue(jiraClient, ops, &issueInput{
+		summary:          summary,
+		projectKey:       project.Key,
+		issueTypeName:    issueType.Name,
+		parentKey:        parentKey,
+		currentUser:      currentUserResult.User,
+		reporterRequired: requiredFieldsResult.fields["reporter"] != nil,
+		customFields:     customFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err = submitIssue(jiraClient, ops, issue, requiredFieldsResult.allFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if ops.DryRun {
+		return issue, nil
+	}
+
+	cfg.SetNested([]string{"configuration", "issue", "projectKey"}, project.Key)
+	if parentKey == "" {
+		// sub-task types can't be used by default as they always need a parent
+		cfg.SetNested([]string{"configuration", "issue", "issueTypeName"}, issueType.Name)
+	}
+	if err := cfg.Write(); err != nil {
+		fmt.Fprintf(ops.Out, "Unable to populate configuration for interactive setup - %s\n", err.Error())
+	}
+
+	return issue, nil
+}
+
+type issueInput struct {
+	summary          string
+	projectKey       string
+	issueTypeName    string
+	parentKey        string
+	currentUser      *jira.User
+	reporterRequired bool
+	customFields     tcontainer.MarshalMap
+}
+
+// newIssue builds create request from collected input and optional flags
+func newIssue(jiraClient *jira.Client, ops *CreateOptions, in *issueInput) (*jira.Issue, error) {
+	assignee := in.currentUser
+	if ops.Assignee != "" {
+		u, err := users.FindUser(jiraClient, ops.Assignee)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve assignee: %w", err)
+		}
+		assignee = u
+	}
+
 	var reporter *jira.User
-	reporterRequired := requiredFieldsResult.fields["reporter"]
-	if reporterRequired {
-		reporter = currentUserResult.User
+	if ops.Reporter != "" {
+		u, err := users.FindUser(jiraClient, ops.Reporter)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve reporter: %w", err)
+		}
+		reporter = u
+	} else if in.reporterRequired {
+		reporter = in.currentUser
 	}
 
-	issue, resp, err := jiraClient.Issue.Create(context.Background(), &jira.Issue{
+	var priority *jira.Priority
+	if ops.Priority != "" {
+		priority = &jira.Priority{Name: ops.Priority}
+	}
+
+	var components []*jira.Component
+	for _, name := range ops.Components {
+		components = append(components, &jira.Component{Name: name})
+	}
+
+	// rest api v3 expects description in atlassian document format,
+	// so it is sent along with custom fields
+	fields := tcontainer.NewMarshalMap()
+	if ops.clone != nil {
+		for k, v := range ops.clone.fields {
+			fields[k] = v
+		}
+	}
+	for k, v := range in.customFields {
+		fields[k] = v
+	}
+	if ops.Description != "" {
+		fields["description"] = adf.FromMarkdown(ops.Description, users.NewMentionResolver(jiraClient))
+	}
+
+	// users are referenced by account id only, full user
+	// objects can't be sent as some of their fields are read only
+	if assignee != nil {
+		fields["assignee"] = map[string]string{"accountId": assignee.AccountID}
+	}
+	if reporter != nil {
+		fields["reporter"] = map[string]string{"accountId": reporter.AccountID}
+	}
+
+	var parent *jira.Parent
+	if in.parentKey != "" {
+		parent = &jira.Parent{Key: in.parentKey}
+		delete(fields, "parent")
+	}
+
+	return &jira.Issue{
 		Fields: &jira.IssueFields{
-			Summary:  summary,
-			Reporter: reporter,
-			Assignee: currentUserResult.User,
+			Parent:     parent,
+			Summary:    in.summary,
+			Priority:   priority,
+			Labels:     ops.Labels,
+			Components: components,
 			Project: jira.Project{
-				Key: project.Key,
+				Key: in.projectKey,
 			},
 			Type: jira.IssueType{
-				Name: issueType.Name,
+				Name: in.issueTypeName,
 			},
+			Unknowns: fields,
 		},
-	})
+	}, nil
+}
+
+// submitIssue creates issue, adds it to requested epic and sprint and links it to other issues,
+// in dry run mode it prints request payload and validates it against createmeta instead
+func submitIssue(jiraClient *jira.Client, ops *CreateOptions, issue *jira.Issue, fields map[string]*FieldMeta) (*jira.Issue, error) {
+	if err := dropInvalidClonedFields(ops, issue, fields); err != nil {
+		return nil, err
+	}
+
+	// sprint and link types are resolved upfront, so that issue is not created when they don't exist
+	sprint, err := resolveSprint(jiraClient, ops, issue.Fields.Project.Key)
 	if err != nil {
-		return nil, utils.ParseJiraResponse(resp)
+		return nil, err
+	}
+	links, err := resolveLinks(jiraClient, ops)
+	if err != nil {
+		return nil, err
+	}
+	duplicateLink, err := checkDuplicates(jiraClient, ops, issue)
+	if err != nil {
+		return nil, err
+	}
+	if duplicateLink != nil {
+		links = append(links, duplicateLink)
 	}
 
-	cfg.SetNested([]string{"configuration", "issue", "projectKey"}, project.Key)
-	cfg.SetNested([]string{"configuration", "issue", "issueTypeName"}, issueType.Name)
-	if err := cfg.Write(); err != nil {
-		fmt.Fprintf(out, "Unable to populate configuration for interactive setup - %s\n", err.Error())
+	if !ops.DryRun {
+		created, err := createIssue(jiraClient, issue)
+		if err != nil {
+			return nil, err
+		}
+		ops.failures = append(ops.failures, planIssue(jiraClient, ops, created.Key, sprint)...)
+		ops.failures = append(ops.failures, linkIssue(jiraClient, created, links)...)
+		return created, nil
+	}
+
+	data, err := json.MarshalIndent(issue, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(ops.Out, string(data))
+
+	if err = validateIssue(issue, fields); err != nil {
+		return nil, fmt.Errorf("issue is not valid: %w", err)
+	}
+
+	if ops.Epic != "" {
+		fmt.Fprintf(ops.Out, "\nepic: %s\n", ops.Epic)
+	}
+	if sprint != nil {
+		fmt.Fprintf(ops.Out, "\nsprint: %s\n", sprint.Name)
+	}
+	if len(links) > 0 {
+		fmt.Fprintln(ops.Out, "\nlinks:")
+		for _, link := range links {
+			fmt.Fprintf(ops.Out, "  %s\n", issues.DescribeLink(link))
+		}
 	}
 
 	return issue, nil
 }
 
+// createIssue creates issue using rest api v3, which unlike
+// go-jira client accepts rich text fields in atlassian document format
+func createIssue(jiraClient *jira.Client, issue *jira.Issue) (*jira.Issue, error) {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost, "rest/api/3/issue", issue)
+	if err != nil {
+		return nil, err
+	}
+
+	created := new(jira.Issue)
+	resp, err := jiraClient.Do(req, created)
+	if err != nil {
+		if resp == nil {
+			return nil, err
+		}
+		return nil, utils.ParseJiraResponse(resp)
+	}
+
+	// jira responds with issue key and id only
+	if created.Fields == nil {
+		created.Fields = issue.Fields
+	}
+
+	return created, nil
+}
+
+// readDescription fills description from file, stdin or editor when requested
+func readDescription(ops *CreateOptions) error {
+	switch {
+	case ops.DescriptionFile == "-":
+		b, err := io.ReadAll(ops.IOStream.In)
+		if err != nil {
+			return fmt.Errorf("cannot read description from stdin: %w", err)
+		}
+		ops.Description = string(b)
+	case ops.DescriptionFile != "":
+		b, err := os.ReadFile(ops.DescriptionFile)
+		if err != nil {
+			return fmt.Errorf("cannot read description file: %w", err)
+		}
+		ops.Description = string(b)
+	case ops.UseEditor:
+		if !ops.IOStream.CanPrompt() {
+			return errors.New("stdin is not a terminal, so jh cannot open an editor. Use --description or --description-file instead")
+		}
+		// description preset by template is offered for editing
+		text, err := ops.Prompter.Editor("Issue Description", ops.Description+adf.EditorHint)
+		if err != nil {
+			return err
+		}
+		ops.Description = adf.StripComments(text)
+	}
+
+	ops.Description = strings.TrimSpace(ops.Description)
+	return nil
+}
+
+// defaultLabels returns labels added to every new issue, they are
+// configured in configuration.issue.labels as a list or comma separated
+func defaultLabels(cfg config.Config) []string {
+	var value interface{}
+	if err := cfg.Decode([]string{"configuration", "issue", "labels"}, &value); err != nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return splitList(v)
+	case []interface{}:
+		var labels []string
+		for _, l := range v {
+			if s, ok := l.(string); ok && s != "" {
+				labels = append(labels, s)
+			}
+		}
+		return labels
+	}
+
+	return nil
+}
+
+// isSubtaskRequested reports whether new issue is going to be a sub-task
+func isSubtaskRequested(ops *CreateOptions) bool {
+	return ops.Parent != "" || ops.SubTask
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
 func normalizeBranchName(branchName, issueKey string) string {
 	return strings.Replace(branchName, "@", strings.ToLower(issueKey), 1)
 }
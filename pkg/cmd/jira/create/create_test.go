@@ -2,11 +2,18 @@ package create
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/MakeNowJust/heredoc"
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 	"github.com/google/shlex"
 	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
@@ -16,7 +23,6 @@ import (
 	"github.com/stirboy/jh/pkg/factory"
 	"github.com/stirboy/jh/pkg/iostreams"
 	"github.com/stretchr/testify/assert"
-	"github.com/trivago/tgo/tcontainer"
 )
 
 func runCreateCommand(f *factory.Factory, args ...string) error {
@@ -177,6 +183,9 @@ func TestCreate_test_interactive_flow(t *testing.T) {
 		inputCalls  int
 		selectCalls int
 		expectErr   bool
+		// noHint is set when issue values are given by flags, so running
+		// interactively is expected
+		noHint bool
 	}{
 		{
 			name:        "should create jira issue",
@@ -188,6 +197,15 @@ func TestCreate_test_interactive_flow(t *testing.T) {
 			selectCalls: 2,
 			expectErr:   false,
 		},
+		{
+			name:        "should create jira issue without hint when summary is given",
+			cfgF:        cfgStubs(),
+			httpStubs:   httpStubs(),
+			promptsF:    promptsStubs(),
+			args:        "--summary 'Fix login'",
+			selectCalls: 2,
+			noHint:      true,
+		},
 		{
 			name:        "should create jira issue with and checkout to new branch (shorthand)",
 			cfgF:        cfgStubs(),
@@ -292,7 +310,11 @@ func TestCreate_test_interactive_flow(t *testing.T) {
 				assert.Equal("Pick issue type", p.SelectCalls()[1].S)
 			}
 
-			assert.Equal("Looks like non-interactive mode was not configured. Running interactively...\n\ncreated issue: https://jira-url/browse/PROJ-1\n", out.String())
+			if tt.noHint {
+				assert.Equal("\ncreated issue: https://jira-url/browse/PROJ-1\n", out.String())
+			} else {
+				assert.Equal("Looks like non-interactive mode was not configured. Running interactively...\n\ncreated issue: https://jira-url/browse/PROJ-1\n", out.String())
+			}
 
 			outBuf := bytes.Buffer{}
 			readConfigF(&outBuf)
@@ -508,34 +530,21 @@ func httpStubs() func(*httpmock.Registry) {
 					ProjectCategory: jira.ProjectCategory{},
 					IssueTypes: []jira.IssueType{
 						{
+							ID:   "10001",
 							Name: "Task",
 						},
 					},
 				},
 			}),
 		)
-		m := tcontainer.NewMarshalMap()
-		taskFields := tcontainer.NewMarshalMap()
-		taskFields["required"] = "true"
-		m.Set("Project", taskFields)
 
-		r.Register(
-			httpmock.REST("GET", "rest/api/2/issue/createmeta"),
-			httpmock.JSONResponse(&jira.CreateMetaInfo{
-				Projects: []*jira.MetaProject{
-					{
-						IssueTypes: []*jira.MetaIssueType{
-							{
-								Fields: m,
-							},
-						},
-					},
-				},
-			}),
-		)
+		createMetaFieldsStub(r, "PROJ", "10001", map[string]interface{}{
+			"key": "project", "required": true,
+		})
+		noDuplicatesStub(r)
 
 		r.Register(
-			httpmock.REST("POST", "rest/api/2/issue"),
+			httpmock.REST("POST", "rest/api/3/issue"),
 			httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
 		)
 	}
@@ -547,33 +556,60 @@ func nonInteractiveHttpStub() func(*httpmock.Registry) {
 			httpmock.REST("GET", "rest/api/3/myself"),
 			httpmock.JSONResponse(&jira.User{}),
 		)
-		m := tcontainer.NewMarshalMap()
-		taskFields := tcontainer.NewMarshalMap()
-		taskFields["required"] = "true"
-		m.Set("Project", taskFields)
+
+		issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+		createMetaFieldsStub(r, "PROJ", "10001", map[string]interface{}{
+			"key": "project", "required": true,
+		})
+		noDuplicatesStub(r)
 
 		r.Register(
-			httpmock.REST("GET", "rest/api/2/issue/createmeta"),
-			httpmock.JSONResponse(&jira.CreateMetaInfo{
-				Projects: []*jira.MetaProject{
-					{
-						IssueTypes: []*jira.MetaIssueType{
-							{
-								Fields: m,
-							},
-						},
-					},
-				},
-			}),
+			httpmock.REST("POST", "rest/api/3/issue"),
+			httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
 		)
+	}
+}
 
+func createMetaHttpStubs() func(*httpmock.Registry) {
+	return func(r *httpmock.Registry) {
 		r.Register(
-			httpmock.REST("POST", "rest/api/2/issue"),
-			httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
+			httpmock.REST("GET", "rest/api/3/myself"),
+			httpmock.JSONResponse(&jira.User{}),
 		)
+
+		issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+		createMetaFieldsStub(r, "PROJ", "10001")
 	}
 }
 
+// noDuplicatesStub stubs search for duplicates done before issue is created in terminal
+func noDuplicatesStub(r *httpmock.Registry) {
+	r.Register(
+		httpmock.REST("GET", "rest/api/3/search/jql"),
+		httpmock.JSONResponse(map[string]interface{}{"issues": []interface{}{}, "isLast": true}),
+	)
+}
+
+func issueTypesStub(r *httpmock.Registry, projectKey string, issueTypes ...jira.IssueType) {
+	r.Register(
+		httpmock.REST("GET", "rest/api/3/issue/createmeta/"+projectKey+"/issuetypes"),
+		httpmock.JSONResponse(map[string]interface{}{
+			"total":      len(issueTypes),
+			"issueTypes": issueTypes,
+		}),
+	)
+}
+
+func createMetaFieldsStub(r *httpmock.Registry, projectKey, issueTypeID string, fields ...map[string]interface{}) {
+	r.Register(
+		httpmock.REST("GET", "rest/api/3/issue/createmeta/"+projectKey+"/issuetypes/"+issueTypeID),
+		httpmock.JSONResponse(map[string]interface{}{
+			"total":  len(fields),
+			"fields": fields,
+		}),
+	)
+}
+
 func promptsStubs() func(pm *prompt.PrompterMock) {
 	return func(pm *prompt.PrompterMock) {
 		pm.InputFunc = func(s1, s2 string, askOpts ...survey.AskOpt) (string, error) {
@@ -584,3 +620,1900 @@ func promptsStubs() func(pm *prompt.PrompterMock) {
 		}
 	}
 }
+
+func TestCreate_test_flags_flow(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfgF         func(*config.ConfigMock)
+		httpStubs    func(*httpmock.Registry)
+		promptStubs  func(*prompt.PrompterMock)
+		args         string
+		stdin        string
+		neverPrompt  bool
+		expectErr    string
+		expectFields map[string]interface{}
+		expectBranch string
+	}{
+		{
+			name:        "should create jira issue from flags without prompts",
+			httpStubs:   createMetaHttpStubs(),
+			args:        "--project PROJ --type Task --summary 'Bump deps' --label deps --label ci --priority High -d 'some text'",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"summary":     "Bump deps",
+				"description": adfDoc(`{"type":"paragraph","content":[{"type":"text","text":"some text"}]}`),
+				"labels":      []interface{}{"deps", "ci"},
+				"priority":    map[string]interface{}{"name": "High"},
+				"project":     map[string]interface{}{"key": "PROJ"},
+				"issuetype":   map[string]interface{}{"name": "Task"},
+			},
+		},
+		{
+			name: "should use stored defaults for missing project and type",
+			cfgF: func(cm *config.ConfigMock) {
+				cm.SetNested([]string{"configuration", "issue", "projectKey"}, "PROJ")
+				cm.SetNested([]string{"configuration", "issue", "issueTypeName"}, "Task")
+			},
+			httpStubs:   createMetaHttpStubs(),
+			args:        "--summary 'Bump deps'",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"summary":   "Bump deps",
+				"project":   map[string]interface{}{"key": "PROJ"},
+				"issuetype": map[string]interface{}{"name": "Task"},
+			},
+		},
+		{
+			name: "should convert markdown description from stdin and resolve mentions",
+			httpStubs: func(r *httpmock.Registry) {
+				createMetaHttpStubs()(r)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"john"}}),
+					httpmock.JSONResponse([]jira.User{{AccountID: "acc-1", DisplayName: "John"}}),
+				)
+			},
+			args:        "-p PROJ -t Task -s 'Bump deps' --description-file -",
+			stdin:       "# Steps\n\n- ask @john\n",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"description": adfDoc(
+					`{"type":"heading","attrs":{"level":1},"content":[{"type":"text","text":"Steps"}]}`,
+					`{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[
+						{"type":"text","text":"ask "},{"type":"mention","attrs":{"id":"acc-1","text":"@john"}}]}]}]}`,
+				),
+			},
+		},
+		{
+			name:      "should write description in editor",
+			httpStubs: createMetaHttpStubs(),
+			promptStubs: func(pm *prompt.PrompterMock) {
+				pm.EditorFunc = func(s1, s2 string) (string, error) {
+					return s2 + "\nfrom `editor`\n", nil
+				}
+			},
+			args: "-p PROJ -t Task -s 'Bump deps' --editor",
+			expectFields: map[string]interface{}{
+				"description": adfDoc(`{"type":"paragraph","content":[{"type":"text","text":"from "},
+					{"type":"text","text":"editor","marks":[{"type":"code"}]}]}`),
+			},
+		},
+		{
+			name:        "should not open editor when stdin is not a terminal",
+			args:        "-p PROJ -t Task -s 'Bump deps' --editor",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot open an editor. Use --description or --description-file instead",
+		},
+		{
+			name:      "should reject several description sources",
+			args:      "-p PROJ -t Task -s 'Bump deps' -d text --editor",
+			expectErr: "if any flags in the group [description description-file editor] are set none of the others can be; [description editor] were all set",
+		},
+		{
+			name: "should create branch named by configured template",
+			cfgF: func(cm *config.ConfigMock) {
+				cm.SetNested([]string{"configuration", "git", "branchTemplate"}, "{type}/{KEY}-{summary}")
+			},
+			httpStubs:    createMetaHttpStubs(),
+			args:         "-p PROJ -t Task -s 'Bump deps: go 1.20' -c",
+			neverPrompt:  true,
+			expectBranch: "task/PROJ-1-bump-deps-go-1-20",
+		},
+		{
+			name: "should add configured labels",
+			cfgF: func(cm *config.ConfigMock) {
+				cm.SetNested([]string{"configuration", "issue", "labels"}, "team, backend")
+			},
+			httpStubs:   createMetaHttpStubs(),
+			args:        "-p PROJ -t Task -s 'Bump deps' -l deps -l team",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"labels": []interface{}{"team", "backend", "deps"},
+			},
+		},
+		{
+			name:        "should list every missing value when stdin is not a terminal",
+			args:        "",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot prompt for input. Missing required values: --summary, --project, --type",
+		},
+		{
+			name: "should ignore stored defaults in interactive mode when stdin is not a terminal",
+			cfgF: func(cm *config.ConfigMock) {
+				cm.SetNested([]string{"configuration", "issue", "projectKey"}, "PROJ")
+				cm.SetNested([]string{"configuration", "issue", "issueTypeName"}, "Task")
+			},
+			args:        "-i --summary 'Bump deps' --type Task",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot prompt for input. Missing required values: --project",
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := config.NewBlankConfig()
+		cfg.Set("url", "url")
+		cfg.Set("username", "username")
+		cfg.Set("token", "token")
+
+		if tt.cfgF != nil {
+			tt.cfgF(cfg)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.httpStubs != nil {
+				tt.httpStubs(reg)
+			}
+
+			var createBody map[string]interface{}
+			if tt.expectErr == "" {
+				if !tt.neverPrompt {
+					noDuplicatesStub(reg)
+				}
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&createBody)
+						return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"})(req)
+					},
+				)
+			}
+
+			gitClient := gitclient.NewGitClientMock()
+
+			// prompter without stubs panics on any prompt
+			p := &prompt.PrompterMock{}
+			if tt.promptStubs != nil {
+				tt.promptStubs(p)
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{
+				In:  strings.NewReader(tt.stdin),
+				Out: out,
+			}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					c := &http.Client{
+						Transport: reg,
+					}
+					return jira.NewClient("https://jira-url", c)
+				},
+				Prompter: p,
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split(tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "\ncreated issue: https://jira-url/browse/PROJ-1\n", out.String())
+
+			if tt.expectBranch != "" {
+				assert.Len(t, gitClient.CreateBranchWithCheckoutCalls(), 1)
+				assert.Equal(t, tt.expectBranch, gitClient.CreateBranchWithCheckoutCalls()[0].S)
+			} else {
+				assert.Empty(t, gitClient.CreateBranchWithCheckoutCalls())
+			}
+
+			fields := createBody["fields"].(map[string]interface{})
+			for k, v := range tt.expectFields {
+				assert.Equal(t, v, fields[k], k)
+			}
+		})
+	}
+}
+
+func TestCreate_test_required_fields(t *testing.T) {
+	requiredFields := []map[string]interface{}{
+		{
+			"key": "summary", "required": true, "name": "Summary", "schema": map[string]interface{}{"type": "string", "system": "summary"},
+		},
+		{
+			"key": "components", "required": true, "name": "Components",
+			"schema":        map[string]interface{}{"type": "array", "items": "component", "system": "components"},
+			"allowedValues": []map[string]interface{}{{"id": "10", "name": "Backend"}, {"id": "11", "name": "Mobile"}},
+		},
+		{
+			"key": "customfield_1", "required": true, "name": "Severity",
+			"schema":        map[string]interface{}{"type": "option", "custom": "select"},
+			"allowedValues": []map[string]interface{}{{"id": "20", "value": "Low"}, {"id": "21", "value": "High"}},
+		},
+		{
+			"key": "customfield_2", "required": true, "name": "Story Points", "schema": map[string]interface{}{"type": "number"},
+		},
+		{
+			"key": "customfield_3", "required": true, "name": "Reviewer", "schema": map[string]interface{}{"type": "user"},
+		},
+		{
+			"key": "customfield_5", "required": true, "name": "Approvers", "schema": map[string]interface{}{"type": "array", "items": "user"},
+		},
+		{
+			"key": "duedate", "required": true, "name": "Due date", "schema": map[string]interface{}{"type": "date", "system": "duedate"},
+		},
+		{
+			"key": "customfield_4", "required": true, "hasDefaultValue": true, "name": "Team", "schema": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		neverPrompt  bool
+		expectErr    string
+		expectFields map[string]interface{}
+	}{
+		{
+			name: "should prompt for every required field",
+			expectFields: map[string]interface{}{
+				"components":    []interface{}{map[string]interface{}{"id": "11"}},
+				"customfield_1": map[string]interface{}{"id": "21"},
+				"customfield_2": float64(3),
+				"customfield_3": map[string]interface{}{"accountId": "acc-2"},
+				"customfield_5": []interface{}{
+					map[string]interface{}{"accountId": "acc-3"},
+					map[string]interface{}{"accountId": "acc-4"},
+				},
+				"duedate": "2023-01-31",
+			},
+		},
+		{
+			name:        "should list missing required fields when stdin is not a terminal",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot prompt for input. Missing required fields: Approvers, Components, Due date, Reviewer, Severity, Story Points",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+
+			reg.Register(
+				httpmock.REST("GET", "rest/api/3/myself"),
+				httpmock.JSONResponse(&jira.User{}),
+			)
+			issueTypesStub(reg, "PROJ", jira.IssueType{ID: "10002", Name: "Bug"})
+			createMetaFieldsStub(reg, "PROJ", "10002", requiredFields...)
+
+			var createBody map[string]interface{}
+			if tt.expectErr == "" {
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"rev"}}),
+					httpmock.JSONResponse([]jira.User{
+						{AccountID: "acc-1", DisplayName: "Reviewer One"},
+						{AccountID: "acc-2", DisplayName: "Reviewer Two"},
+					}),
+				)
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"ann"}}),
+					httpmock.JSONResponse([]jira.User{{AccountID: "acc-3", DisplayName: "Ann"}}),
+				)
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"bob"}}),
+					httpmock.JSONResponse([]jira.User{{AccountID: "acc-4", DisplayName: "Bob"}}),
+				)
+				if !tt.neverPrompt {
+					noDuplicatesStub(reg)
+				}
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&createBody)
+						return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"})(req)
+					},
+				)
+			}
+
+			// approvers are asked until the answer is empty
+			approvers := []string{"bob", ""}
+			p := &prompt.PrompterMock{
+				InputFunc: func(s1, s2 string, askOpts ...survey.AskOpt) (string, error) {
+					switch s1 {
+					case "Approvers (search by name or email)":
+						return "ann", nil
+					case "Approvers (search by name or email, empty to finish)":
+						answer := approvers[0]
+						approvers = approvers[1:]
+						return answer, nil
+					case "Story Points":
+						return "3", nil
+					case "Due date (YYYY-MM-DD)":
+						return "2023-01-31", nil
+					case "Reviewer (search by name or email)":
+						return "rev", nil
+					}
+					return "", fmt.Errorf("unexpected input prompt %q", s1)
+				},
+				SelectFunc: func(s string, options []string) (string, error) {
+					return options[len(options)-1], nil
+				},
+				MultiSelectFunc: func(s string, options []string) ([]string, error) {
+					return []string{"Mobile"}, nil
+				},
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{
+				Out: out,
+			}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			cfg := config.NewBlankConfig()
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					c := &http.Client{
+						Transport: reg,
+					}
+					return jira.NewClient("https://jira-url", c)
+				},
+				Prompter: p,
+				IOStream: io,
+			}
+
+			// when
+			err := runCreateCommand(factory, "--project", "PROJ", "--type", "Bug", "--summary", "Crash on start")
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Pick Components", p.MultiSelectCalls()[0].S)
+
+			fields := createBody["fields"].(map[string]interface{})
+			for k, v := range tt.expectFields {
+				assert.Equal(t, v, fields[k], k)
+			}
+			assert.NotContains(t, fields, "customfield_4")
+		})
+	}
+}
+
+func TestCreate_test_required_fields_of_selected_issue_type(t *testing.T) {
+	// given
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.REST("GET", "rest/api/3/myself"),
+		httpmock.JSONResponse(&jira.User{}),
+	)
+	reg.Register(
+		httpmock.REST("GET", "rest/api/3/project/recent"),
+		httpmock.JSONResponse(jira.ProjectList{
+			{
+				Key: "PROJ",
+				IssueTypes: []jira.IssueType{
+					{ID: "10001", Name: "Task"},
+					{ID: "10002", Name: "Bug"},
+				},
+			},
+		}),
+	)
+	// fields of selected issue type are split between two pages
+	reg.Register(
+		httpmock.QueryMatcher("GET", "rest/api/3/issue/createmeta/PROJ/issuetypes/10002", url.Values{"startAt": []string{"0"}}),
+		httpmock.JSONResponse(map[string]interface{}{
+			"total":  2,
+			"fields": []map[string]interface{}{{"key": "summary", "required": true}},
+		}),
+	)
+	reg.Register(
+		httpmock.QueryMatcher("GET", "rest/api/3/issue/createmeta/PROJ/issuetypes/10002", url.Values{"startAt": []string{"1"}}),
+		httpmock.JSONResponse(map[string]interface{}{
+			"total": 2,
+			"fields": []map[string]interface{}{{
+				"key": "customfield_9", "name": "Environment", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			}},
+		}),
+	)
+
+	var createBody map[string]interface{}
+	noDuplicatesStub(reg)
+	reg.Register(
+		httpmock.REST("POST", "rest/api/3/issue"),
+		func(req *http.Request) (*http.Response, error) {
+			_ = json.NewDecoder(req.Body).Decode(&createBody)
+			return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"})(req)
+		},
+	)
+
+	p := &prompt.PrompterMock{
+		InputFunc: func(s1, s2 string, askOpts ...survey.AskOpt) (string, error) {
+			return "staging", nil
+		},
+		SelectFunc: func(s string, options []string) (string, error) {
+			// picks "Bug" as issue types are sorted
+			return options[0], nil
+		},
+	}
+
+	config.StubWriteConfig(t)
+	cfg := config.NewBlankConfig()
+	factory := &factory.Factory{
+		Config: func() (config.Config, error) {
+			return cfg, nil
+		},
+		JiraClient: func() (*jira.Client, error) {
+			c := &http.Client{
+				Transport: reg,
+			}
+			return jira.NewClient("https://jira-url", c)
+		},
+		Prompter: p,
+		IOStream: &iostreams.IOStream{
+			Out: &bytes.Buffer{},
+		},
+	}
+
+	// when
+	err := runCreateCommand(factory, "-i", "--summary", "Crash on start")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Environment", p.InputCalls()[0].S1)
+
+	fields := createBody["fields"].(map[string]interface{})
+	assert.Equal(t, "staging", fields["customfield_9"])
+	assert.Equal(t, map[string]interface{}{"name": "Bug"}, fields["issuetype"])
+}
+
+// adfDoc builds expected description document from json encoded nodes
+func adfDoc(nodes ...string) map[string]interface{} {
+	var doc map[string]interface{}
+	raw := fmt.Sprintf(`{"type":"doc","version":1,"content":[%s]}`, strings.Join(nodes, ","))
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+func TestCreate_test_template(t *testing.T) {
+	cfgTemplates := heredoc.Doc(`
+		url: url
+		username: username
+		token: token
+		configuration:
+		  templates:
+		    bug:
+		      project: PROJ
+		      issueType: Task
+		      summary: "Bug: {{title}}"
+		      labels: [bug]
+		      components: [Backend]
+		      priority: High
+		      description: |
+		        ## Steps
+		        {{steps}}
+		    debt:
+		      project: PROJ
+	`)
+
+	tests := []struct {
+		name         string
+		repoTemplate string
+		args         string
+		neverPrompt  bool
+		expectErr    string
+		expectFields map[string]interface{}
+	}{
+		{
+			name: "should merge config template with flags and prompt for placeholders",
+			args: "--template bug --var title=Crash --label ui",
+			expectFields: map[string]interface{}{
+				"summary":     "Bug: Crash",
+				"labels":      []interface{}{"bug", "ui"},
+				"components":  []interface{}{map[string]interface{}{"name": "Backend"}},
+				"priority":    map[string]interface{}{"name": "High"},
+				"issuetype":   map[string]interface{}{"name": "Task"},
+				"description": adfDoc(`{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]}`, `{"type":"paragraph","content":[{"type":"text","text":"open app"}]}`),
+			},
+		},
+		{
+			name:         "should prefer template from repository",
+			repoTemplate: "project: PROJ\nissueType: Task\nsummary: Repo bug\nlabels: [repo]\n",
+			args:         "--template bug",
+			neverPrompt:  true,
+			expectFields: map[string]interface{}{
+				"summary": "Repo bug",
+				"labels":  []interface{}{"repo"},
+			},
+		},
+		{
+			name:        "should fail when placeholders cannot be prompted",
+			args:        "--template bug",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot prompt for input. Missing template values: title, steps (use --var name=value)",
+		},
+		{
+			name:      "should list available templates",
+			args:      "--template feature",
+			expectErr: `template "feature" not found, available templates: bug, debt`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+
+			var createBody map[string]interface{}
+			if tt.expectErr == "" {
+				createMetaHttpStubs()(reg)
+				if !tt.neverPrompt {
+					noDuplicatesStub(reg)
+				}
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&createBody)
+						return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"})(req)
+					},
+				)
+			}
+
+			repoDir := t.TempDir()
+			if tt.repoTemplate != "" {
+				dir := filepath.Join(repoDir, ".jh", "templates")
+				assert.NoError(t, os.MkdirAll(dir, 0755))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "bug.yml"), []byte(tt.repoTemplate), 0644))
+			}
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.RootDirFunc = func() (string, error) {
+				return repoDir, nil
+			}
+
+			p := &prompt.PrompterMock{
+				InputFunc: func(s1, s2 string, askOpts ...survey.AskOpt) (string, error) {
+					assert.Equal(t, "steps", s1)
+					return "open app", nil
+				},
+			}
+
+			io := &iostreams.IOStream{
+				Out: &bytes.Buffer{},
+			}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			cfg := config.NewFromString(cfgTemplates)
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: p,
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split(tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			fields := createBody["fields"].(map[string]interface{})
+			for k, v := range tt.expectFields {
+				assert.Equal(t, v, fields[k], k)
+			}
+		})
+	}
+}
+
+func TestCreate_test_subtask(t *testing.T) {
+	recentProjectsStub := func(r *httpmock.Registry) {
+		r.Register(
+			httpmock.REST("GET", "rest/api/3/myself"),
+			httpmock.JSONResponse(&jira.User{}),
+		)
+		r.Register(
+			httpmock.REST("GET", "rest/api/3/project/recent"),
+			httpmock.JSONResponse(jira.ProjectList{
+				{
+					Key: "PROJ",
+					IssueTypes: []jira.IssueType{
+						{ID: "10001", Name: "Task"},
+						{ID: "10003", Name: "Sub-task", Subtask: true},
+					},
+				},
+			}),
+		)
+	}
+
+	tests := []struct {
+		name         string
+		httpStubs    func(*httpmock.Registry)
+		args         string
+		neverPrompt  bool
+		expectErr    string
+		expectParent interface{}
+	}{
+		{
+			name: "should offer only sub-task types when parent is given",
+			httpStubs: func(r *httpmock.Registry) {
+				recentProjectsStub(r)
+				createMetaFieldsStub(r, "PROJ", "10003", map[string]interface{}{
+					"key": "parent", "required": true, "name": "Parent",
+				})
+			},
+			args:         "-p PROJ -s 'Write tests' --parent PROJ-1",
+			expectParent: map[string]interface{}{"key": "PROJ-1"},
+		},
+		{
+			name: "should pick parent among open issues",
+			httpStubs: func(r *httpmock.Registry) {
+				recentProjectsStub(r)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{`project = "PROJ" AND statusCategory != Done AND issuetype not in subTaskIssueTypes() ` +
+							`AND text ~ "login" ORDER BY updated DESC`},
+					}),
+					httpmock.JSONResponse(map[string]interface{}{
+						"issues": []map[string]interface{}{
+							{"key": "PROJ-5", "fields": map[string]interface{}{"summary": "Login page"}},
+						},
+						"isLast": true,
+					}),
+				)
+				createMetaFieldsStub(r, "PROJ", "10003")
+			},
+			args:         "-p PROJ -s 'Write tests' --subtask",
+			expectParent: map[string]interface{}{"key": "PROJ-5"},
+		},
+		{
+			name: "should create sub-task of given type",
+			httpStubs: func(r *httpmock.Registry) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"},
+					jira.IssueType{ID: "10003", Name: "Sub-task", Subtask: true})
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/myself"),
+					httpmock.JSONResponse(&jira.User{}),
+				)
+				createMetaFieldsStub(r, "PROJ", "10003")
+			},
+			args:         "-p PROJ -t sub-task -s 'Write tests' --parent PROJ-1",
+			neverPrompt:  true,
+			expectParent: map[string]interface{}{"key": "PROJ-1"},
+		},
+		{
+			name: "should list sub-task types when type given with parent is not a sub-task",
+			httpStubs: func(r *httpmock.Registry) {
+				issueTypesStub(r, "PROJ", jira.IssueType{ID: "10001", Name: "Task"},
+					jira.IssueType{ID: "10003", Name: "Sub-task", Subtask: true},
+					jira.IssueType{ID: "10004", Name: "Sub-bug", Subtask: true})
+			},
+			args:        "-p PROJ -t Task -s 'Write tests' --parent PROJ-1",
+			neverPrompt: true,
+			expectErr:   "issue type Task cannot have parent, sub-task issue types of project PROJ: Sub-bug, Sub-task",
+		},
+		{
+			name:        "should require parent and type when stdin is not a terminal",
+			args:        "-p PROJ -s 'Write tests' --subtask",
+			neverPrompt: true,
+			expectErr:   "stdin is not a terminal, so jh cannot prompt for input. Missing required values: --type, --parent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			config.StubWriteConfig(t)
+			cfg := config.NewBlankConfig()
+
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.httpStubs != nil {
+				tt.httpStubs(reg)
+			}
+
+			var createBody map[string]interface{}
+			if tt.expectErr == "" {
+				if !tt.neverPrompt {
+					noDuplicatesStub(reg)
+				}
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&createBody)
+						return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-9"})(req)
+					},
+				)
+			}
+
+			p := &prompt.PrompterMock{
+				InputFunc: func(s1, s2 string, askOpts ...survey.AskOpt) (string, error) {
+					return "login", nil
+				},
+				SelectFunc: func(s string, options []string) (string, error) {
+					switch s {
+					case "Pick issue type":
+						assert.Equal(t, []string{"Sub-task"}, options)
+					case "Pick parent issue":
+						assert.Equal(t, []string{"PROJ-5 Login page"}, options)
+					}
+					return options[0], nil
+				},
+			}
+
+			io := &iostreams.IOStream{Out: &bytes.Buffer{}}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: p,
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split(tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			fields := createBody["fields"].(map[string]interface{})
+			assert.Equal(t, tt.expectParent, fields["parent"])
+			assert.Equal(t, map[string]interface{}{"name": "Sub-task"}, fields["issuetype"])
+
+			// sub-task type is not remembered as default
+			_, err = cfg.GetNested([]string{"configuration", "issue", "issueTypeName"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCreate_test_dry_run(t *testing.T) {
+	screenFields := []map[string]interface{}{
+		{"key": "summary", "name": "Summary", "required": true},
+		{"key": "assignee", "name": "Assignee"},
+		{
+			"key": "priority", "name": "Priority",
+			"allowedValues": []map[string]interface{}{{"id": "1", "name": "High"}, {"id": "2", "name": "Low"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      string
+		expectOut string
+		expectErr string
+	}{
+		{
+			name: "should print issue and branch without creating them",
+			args: "-p PROJ -t Task -s 'Fix login' --priority High -b '{KEY}-{summary}' --dry-run",
+			expectOut: heredoc.Doc(`
+				{
+				  "fields": {
+				    "assignee": {
+				      "accountId": "me"
+				    },
+				    "issuetype": {
+				      "name": "Task"
+				    },
+				    "priority": {
+				      "name": "High"
+				    },
+				    "project": {
+				      "key": "PROJ"
+				    },
+				    "summary": "Fix login"
+				  }
+				}
+
+				branch: PROJ-NEW-fix-login
+
+				dry run: issue was not created
+			`),
+		},
+		{
+			name:      "should fail when issue would be rejected",
+			args:      "-p PROJ -t Task -s 'Fix login' --priority Urgent -l ui --dry-run",
+			expectErr: `issue is not valid: field labels cannot be set, it is not on the create screen; "Urgent" is not allowed for Priority`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(
+				httpmock.REST("GET", "rest/api/3/myself"),
+				httpmock.JSONResponse(&jira.User{AccountID: "me"}),
+			)
+			issueTypesStub(reg, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+			createMetaFieldsStub(reg, "PROJ", "10001", screenFields...)
+
+			gitClient := gitclient.NewGitClientMock()
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split(tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			assert.Empty(t, gitClient.CreateBranchWithCheckoutCalls())
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+		})
+	}
+}
+
+func TestCreate_test_epic_and_sprint(t *testing.T) {
+	sprintsStub := func(r *httpmock.Registry, boardID, state string, sprints ...map[string]interface{}) {
+		r.Register(
+			httpmock.QueryMatcher("GET", "rest/agile/1.0/board/"+boardID+"/sprint", url.Values{
+				"state": []string{state},
+			}),
+			httpmock.JSONResponse(map[string]interface{}{"values": sprints, "isLast": true}),
+		)
+	}
+	boardsStub := func(r *httpmock.Registry, boards ...map[string]interface{}) {
+		r.Register(
+			httpmock.QueryMatcher("GET", "rest/agile/1.0/board", url.Values{
+				"projectKeyOrId": []string{"PROJ"},
+				"type":           []string{"scrum"},
+			}),
+			httpmock.JSONResponse(map[string]interface{}{"values": boards, "isLast": true}),
+		)
+	}
+
+	tests := []struct {
+		name         string
+		config       string
+		args         string
+		httpStubs    func(*httpmock.Registry)
+		expectCreate bool
+		expectEpic   bool
+		expectSprint string
+		expectBoard  string
+		expectErr    string
+	}{
+		{
+			name:   "should add issue to epic and active sprint of remembered board",
+			config: "configuration:\n  boards:\n    PROJ: \"5\"\n",
+			args:   "--epic PROJ-7 --sprint active",
+			httpStubs: func(r *httpmock.Registry) {
+				sprintsStub(r, "5", "active", map[string]interface{}{"id": 12, "name": "Sprint 4", "state": "active"})
+			},
+			expectCreate: true,
+			expectEpic:   true,
+			expectSprint: "12",
+			expectBoard:  "5",
+		},
+		{
+			name: "should find board of project and remember it",
+			args: "--sprint next",
+			httpStubs: func(r *httpmock.Registry) {
+				boardsStub(r, map[string]interface{}{"id": 3, "name": "PROJ board", "type": "scrum"})
+				sprintsStub(r, "3", "future",
+					map[string]interface{}{"id": 8, "name": "Sprint 8", "state": "future"},
+					map[string]interface{}{"id": 9, "name": "Sprint 9", "state": "future"},
+				)
+			},
+			expectCreate: true,
+			expectSprint: "8",
+			expectBoard:  "3",
+		},
+		{
+			name:   "should find sprint by name",
+			config: "configuration:\n  boards:\n    PROJ: \"5\"\n",
+			args:   "--sprint 'sprint 9'",
+			httpStubs: func(r *httpmock.Registry) {
+				sprintsStub(r, "5", "active,future",
+					map[string]interface{}{"id": 8, "name": "Sprint 8", "state": "active"},
+					map[string]interface{}{"id": 9, "name": "Sprint 9", "state": "future"},
+				)
+			},
+			expectCreate: true,
+			expectSprint: "9",
+			expectBoard:  "5",
+		},
+		{
+			name:   "should not create issue when sprint is not found",
+			config: "configuration:\n  boards:\n    PROJ: \"5\"\n",
+			args:   "--sprint 'Sprint 1'",
+			httpStubs: func(r *httpmock.Registry) {
+				sprintsStub(r, "5", "active,future",
+					map[string]interface{}{"id": 8, "name": "Sprint 8", "state": "active"},
+				)
+			},
+			expectErr: `sprint "Sprint 1" not found on board 5, open sprints are: Sprint 8`,
+		},
+		{
+			name: "should ask to choose board when project has many of them",
+			args: "--sprint active",
+			httpStubs: func(r *httpmock.Registry) {
+				boardsStub(r,
+					map[string]interface{}{"id": 3, "name": "Backend", "type": "scrum"},
+					map[string]interface{}{"id": 4, "name": "Frontend", "type": "scrum"},
+				)
+			},
+			expectErr: "project PROJ has 2 scrum boards, choose one with `jh config set configuration.boards.PROJ <id>`: 3 Backend, 4 Frontend",
+		},
+		{
+			name:   "should move issue to sprint and report issue key when epic can't be set",
+			config: "configuration:\n  boards:\n    PROJ: \"5\"\n",
+			args:   "--epic PROJ-7 --sprint active",
+			httpStubs: func(r *httpmock.Registry) {
+				sprintsStub(r, "5", "active", map[string]interface{}{"id": 12, "name": "Sprint 4", "state": "active"})
+				r.Register(
+					httpmock.REST("POST", "rest/agile/1.0/epic/PROJ-7/issue"),
+					httpmock.StatusStringResponse(404, `{"errorMessages":["Issue does not exist"]}`),
+				)
+			},
+			expectCreate: true,
+			expectSprint: "12",
+			expectBoard:  "5",
+			expectErr:    "issue https://jira-url/browse/PROJ-1 was created, but:\n  cannot add issue to epic PROJ-7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			config.StubWriteConfig(t)
+			cfg := config.NewFromString(tt.config)
+
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			createMetaHttpStubs()(reg)
+			if tt.httpStubs != nil {
+				tt.httpStubs(reg)
+			}
+			if tt.expectCreate {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
+				)
+			}
+
+			var epicBody, sprintBody map[string]interface{}
+			if tt.expectEpic {
+				reg.Register(
+					httpmock.REST("POST", "rest/agile/1.0/epic/PROJ-7/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&epicBody)
+						return httpmock.StatusStringResponse(204, "")(req)
+					},
+				)
+			}
+			if tt.expectSprint != "" {
+				reg.Register(
+					httpmock.REST("POST", "rest/agile/1.0/sprint/"+tt.expectSprint+"/issue"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&sprintBody)
+						return httpmock.StatusStringResponse(204, "")(req)
+					},
+				)
+			}
+
+			io := &iostreams.IOStream{Out: &bytes.Buffer{}}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return cfg, nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split("-p PROJ -t Task -s 'Fix login' " + tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				if !tt.expectCreate {
+					return
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectEpic {
+				assert.Equal(t, map[string]interface{}{"issues": []interface{}{"PROJ-1"}}, epicBody)
+			}
+			assert.Equal(t, map[string]interface{}{"issues": []interface{}{"PROJ-1"}}, sprintBody)
+
+			board, _ := cfg.GetNested([]string{"configuration", "boards", "PROJ"})
+			assert.Equal(t, tt.expectBoard, board)
+		})
+	}
+}
+
+func TestCreate_test_links(t *testing.T) {
+	linkTypes := map[string]interface{}{
+		"issueLinkTypes": []map[string]interface{}{
+			{"id": "1", "name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+			{"id": "2", "name": "Relates", "inward": "relates to", "outward": "relates to"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        string
+		linkTypes   interface{}
+		expectLinks []string
+		expectOut   string
+		expectErr   string
+	}{
+		{
+			name: "should link created issue to other issues",
+			args: "--blocks PROJ-3 --blocked-by PROJ-4 --relates-to PROJ-5,PROJ-6",
+			expectLinks: []string{
+				"Blocks PROJ-1 PROJ-3",
+				"Blocks PROJ-4 PROJ-1",
+				"Relates PROJ-1 PROJ-5",
+				"Relates PROJ-1 PROJ-6",
+			},
+			expectOut: heredoc.Doc(`
+
+				created issue: https://jira-url/browse/PROJ-1
+				  blocks PROJ-3
+				  is blocked by PROJ-4
+				  relates to PROJ-5
+				  relates to PROJ-6
+			`),
+		},
+		{
+			name: "should not create issue when link type is not found",
+			args: "--blocks PROJ-3",
+			linkTypes: map[string]interface{}{
+				"issueLinkTypes": []map[string]interface{}{
+					{"id": "2", "name": "Relates", "inward": "relates to", "outward": "relates to"},
+				},
+			},
+			expectErr: `link type "Blocks" not found, available link types are: Relates`,
+		},
+		{
+			name: "should print links in dry run",
+			args: "--blocked-by PROJ-4 --dry-run",
+			expectOut: heredoc.Doc(`
+
+				links:
+				  is blocked by PROJ-4
+
+				dry run: issue was not created
+			`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(
+				httpmock.REST("GET", "rest/api/3/myself"),
+				httpmock.JSONResponse(&jira.User{}),
+			)
+			issueTypesStub(reg, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+			createMetaFieldsStub(reg, "PROJ", "10001",
+				map[string]interface{}{"key": "summary", "name": "Summary", "required": true},
+				map[string]interface{}{"key": "assignee", "name": "Assignee"},
+			)
+
+			types := tt.linkTypes
+			if types == nil {
+				types = linkTypes
+			}
+			reg.Register(httpmock.REST("GET", "rest/api/3/issueLinkType"), httpmock.JSONResponse(types))
+
+			if len(tt.expectLinks) > 0 {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
+				)
+			}
+			var links []string
+			for range tt.expectLinks {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issueLink"),
+					func(req *http.Request) (*http.Response, error) {
+						var body struct {
+							Type         struct{ Name string }
+							InwardIssue  struct{ Key string }
+							OutwardIssue struct{ Key string }
+						}
+						_ = json.NewDecoder(req.Body).Decode(&body)
+						links = append(links, body.Type.Name+" "+body.InwardIssue.Key+" "+body.OutwardIssue.Key)
+						return httpmock.StatusStringResponse(201, "")(req)
+					},
+				)
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split("-p PROJ -t Task -s 'Fix login' " + tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectLinks, links)
+			assert.True(t, strings.HasSuffix(out.String(), tt.expectOut), out.String())
+		})
+	}
+}
+
+func TestCreate_test_attachments(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "crash.log")
+	pngPath := filepath.Join(dir, "screen.png")
+	assert.NoError(t, os.WriteFile(logPath, []byte("panic: boom"), 0644))
+	assert.NoError(t, os.WriteFile(pngPath, []byte("png"), 0644))
+
+	tests := []struct {
+		name         string
+		args         []string
+		uploadStatus []int
+		expectFiles  map[string]string
+		expectOut    string
+		expectErr    string
+	}{
+		{
+			name:         "should upload every file and create branch despite failed upload",
+			args:         []string{"--attach", logPath, "--attach", pngPath, "-b", "{key}"},
+			uploadStatus: []int{200, 413},
+			expectFiles:  map[string]string{"crash.log": "panic: boom", "screen.png": "png"},
+			expectOut: heredoc.Doc(`
+
+				created issue: https://jira-url/browse/PROJ-1
+
+				attached crash.log
+				failed to attach screen.png
+			`),
+			expectErr: "issue https://jira-url/browse/PROJ-1 was created, but:\n  cannot attach " + pngPath + ": file is too large",
+		},
+		{
+			name:      "should not create issue when file doesn't exist",
+			args:      []string{"--attach", filepath.Join(dir, "missing.txt")},
+			expectErr: "cannot attach file: stat " + filepath.Join(dir, "missing.txt") + ": no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if len(tt.uploadStatus) > 0 {
+				createMetaHttpStubs()(reg)
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
+				)
+			}
+
+			files := map[string]string{}
+			for _, status := range tt.uploadStatus {
+				status := status
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue/PROJ-1/attachments"),
+					func(req *http.Request) (*http.Response, error) {
+						assert.Equal(t, "nocheck", req.Header.Get("X-Atlassian-Token"))
+						mr, err := req.MultipartReader()
+						assert.NoError(t, err)
+						part, err := mr.NextPart()
+						assert.NoError(t, err)
+						content := &bytes.Buffer{}
+						_, _ = content.ReadFrom(part)
+						files[part.FileName()] = content.String()
+						if status != 200 {
+							return httpmock.StatusStringResponse(status, `{"errorMessages":["file is too large"]}`)(req)
+						}
+						return httpmock.StatusStringResponse(status, "[]")(req)
+					},
+				)
+			}
+
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CreateBranchWithCheckoutFunc = func(s string) error {
+				return nil
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: io,
+			}
+
+			// when
+			err := runCreateCommand(factory, append([]string{"-p", "PROJ", "-t", "Task", "-s", "Fix login"}, tt.args...)...)
+
+			// then
+			assert.EqualError(t, err, tt.expectErr)
+			if len(tt.uploadStatus) == 0 {
+				assert.Empty(t, out.String())
+				return
+			}
+
+			assert.Equal(t, tt.expectFiles, files)
+			assert.Equal(t, tt.expectOut, out.String())
+			assert.Len(t, gitClient.CreateBranchWithCheckoutCalls(), 1)
+		})
+	}
+}
+
+func TestCreate_test_partial_failure(t *testing.T) {
+	// given
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+	createMetaHttpStubs()(reg)
+	reg.Register(httpmock.REST("GET", "rest/api/3/issueLinkType"), httpmock.JSONResponse(map[string]interface{}{
+		"issueLinkTypes": []map[string]interface{}{
+			{"id": "1", "name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+			{"id": "2", "name": "Relates", "inward": "relates to", "outward": "relates to"},
+		},
+	}))
+	reg.Register(httpmock.REST("POST", "rest/api/3/issue"), httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}))
+	reg.Register(
+		httpmock.REST("POST", "rest/api/3/issueLink"),
+		httpmock.StatusStringResponse(404, `{"errorMessages":["Issue PROJ-3 does not exist"]}`),
+	)
+	reg.Register(httpmock.REST("POST", "rest/api/3/issueLink"), httpmock.StatusStringResponse(201, ""))
+	reg.Register(
+		httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+		httpmock.StringResponse(`{"key": "PROJ-1", "fields": {"summary": "Fix login"}}`),
+	)
+
+	gitClient := gitclient.NewGitClientMock()
+	gitClient.CreateBranchWithCheckoutFunc = func(s string) error {
+		return errors.New("jh create branch failed: a branch named 'PROJ-1' already exists")
+	}
+
+	out := &bytes.Buffer{}
+	io := &iostreams.IOStream{Out: out}
+	io.SetNeverPrompt(true)
+
+	factory := &factory.Factory{
+		Config: func() (config.Config, error) {
+			return config.NewBlankConfig(), nil
+		},
+		JiraClient: func() (*jira.Client, error) {
+			return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+		},
+		Prompter: &prompt.PrompterMock{},
+		GitClient: func() (gitclient.GitClient, error) {
+			return gitClient, nil
+		},
+		IOStream: io,
+	}
+
+	// when
+	err := runCreateCommand(factory, "-p", "PROJ", "-t", "Task", "-s", "Fix login",
+		"--blocks", "PROJ-3", "--relates-to", "PROJ-5", "-b", "{key}", "--jq", ".key")
+
+	// then every step is done and created issue is exported despite failures
+	assert.ErrorContains(t, err, "issue https://jira-url/browse/PROJ-1 was created, but:\n"+
+		"  cannot create link \"blocks PROJ-3\": ")
+	assert.ErrorContains(t, err, "\n  jh create branch failed: a branch named 'PROJ-1' already exists")
+	assert.Len(t, gitClient.CreateBranchWithCheckoutCalls(), 1)
+	assert.Equal(t, "PROJ-1\n", out.String())
+}
+
+func TestCreate_formatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KB", formatSize(1536))
+	assert.Equal(t, "2.0 MB", formatSize(2*1024*1024))
+}
+
+func TestCreate_test_duplicates(t *testing.T) {
+	duplicatesStub := func(r *httpmock.Registry) {
+		r.Register(
+			httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+				"jql": []string{`project = "PROJ" AND statusCategory != Done AND summary ~ "Login fails on Safari" ORDER BY updated DESC`},
+			}),
+			httpmock.JSONResponse(map[string]interface{}{
+				"issues": []map[string]interface{}{
+					{"key": "PROJ-3", "fields": map[string]interface{}{"summary": "Login fails"}},
+				},
+				"isLast": true,
+			}),
+		)
+	}
+
+	tests := []struct {
+		name          string
+		config        string
+		args          string
+		neverPrompt   bool
+		choice        string
+		httpStubs     func(*httpmock.Registry)
+		expectCreate  bool
+		expectLink    string
+		expectBrowser string
+		expectErr     string
+	}{
+		{
+			name:   "should create issue linked as duplicate",
+			choice: "Create and link as duplicate of PROJ-3 Login fails",
+			httpStubs: func(r *httpmock.Registry) {
+				duplicatesStub(r)
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issueLinkType"),
+					httpmock.JSONResponse(map[string]interface{}{
+						"issueLinkTypes": []map[string]interface{}{
+							{"id": "3", "name": "Duplicate", "inward": "is duplicated by", "outward": "duplicates"},
+						},
+					}),
+				)
+			},
+			expectCreate: true,
+			expectLink:   "Duplicate PROJ-1 PROJ-3",
+		},
+		{
+			name:          "should open existing issue instead of creating new one",
+			choice:        "Open PROJ-3 Login fails",
+			httpStubs:     duplicatesStub,
+			expectBrowser: "https://jira-url/browse/PROJ-3",
+		},
+		{
+			name:         "should create issue anyway",
+			choice:       "Create anyway",
+			httpStubs:    duplicatesStub,
+			expectCreate: true,
+		},
+		{
+			name:         "should not check duplicates when turned off for project",
+			config:       "configuration:\n  checkDuplicates:\n    PROJ: false\n",
+			expectCreate: true,
+		},
+		{
+			name:         "should not check duplicates when stdin is not a terminal",
+			neverPrompt:  true,
+			expectCreate: true,
+		},
+		{
+			name:        "should fail on duplicates when check is requested without terminal",
+			args:        "--check-duplicates",
+			neverPrompt: true,
+			httpStubs:   duplicatesStub,
+			expectErr:   "possible duplicates found: PROJ-3 Login fails. Use --check-duplicates=false to create issue anyway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			createMetaHttpStubs()(reg)
+			if tt.httpStubs != nil {
+				tt.httpStubs(reg)
+			}
+			if tt.expectCreate {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"}),
+				)
+			}
+			var link string
+			if tt.expectLink != "" {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issueLink"),
+					func(req *http.Request) (*http.Response, error) {
+						var body struct {
+							Type         struct{ Name string }
+							InwardIssue  struct{ Key string }
+							OutwardIssue struct{ Key string }
+						}
+						_ = json.NewDecoder(req.Body).Decode(&body)
+						link = body.Type.Name + " " + body.InwardIssue.Key + " " + body.OutwardIssue.Key
+						return httpmock.StatusStringResponse(201, "")(req)
+					},
+				)
+			}
+
+			p := &prompt.PrompterMock{
+				SelectFunc: func(s string, options []string) (string, error) {
+					assert.Equal(t, "Possible duplicates found", s)
+					assert.Equal(t, []string{
+						"Create anyway",
+						"Open PROJ-3 Login fails",
+						"Create and link as duplicate of PROJ-3 Login fails",
+					}, options)
+					return tt.choice, nil
+				},
+			}
+
+			var browsed string
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewFromString(tt.config), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: p,
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+				Browser: func(url string) error {
+					browsed = url
+					return nil
+				},
+			}
+
+			argv, err := shlex.Split("-p PROJ -t Task -s 'Login fails on Safari!' " + tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectLink, link)
+			assert.Equal(t, tt.expectBrowser, browsed)
+			if tt.expectLink != "" {
+				assert.Contains(t, out.String(), "created issue: https://jira-url/browse/PROJ-1\n  duplicates PROJ-3\n")
+			}
+			if !tt.expectCreate {
+				assert.NotContains(t, out.String(), "created issue")
+			}
+		})
+	}
+}
+
+func TestCreate_test_clone(t *testing.T) {
+	source := map[string]interface{}{
+		"key": "PROJ-2",
+		"fields": map[string]interface{}{
+			"summary":         "Login fails",
+			"project":         map[string]interface{}{"key": "PROJ"},
+			"issuetype":       map[string]interface{}{"name": "Task"},
+			"labels":          []string{"web"},
+			"components":      []map[string]interface{}{{"id": "7", "name": "UI"}},
+			"priority":        map[string]interface{}{"id": "2", "name": "High"},
+			"description":     adfDoc(`{"type":"paragraph","content":[{"type":"text","text":"Steps"}]}`),
+			"customfield_100": map[string]interface{}{"id": "1", "value": "Safari", "self": "https://jira-url/option/1"},
+			"customfield_200": "not on screen",
+			"customfield_300": map[string]interface{}{"id": "9", "value": "Removed"},
+			"customfield_400": nil,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		args          string
+		neverPrompt   bool
+		choices       []string
+		input         string
+		expectFields  map[string]interface{}
+		expectMissing []string
+		expectOut     []string
+	}{
+		{
+			name:        "should copy allowed fields and link issue to its source",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"summary":         "Login fails",
+				"labels":          []interface{}{"web"},
+				"components":      []interface{}{map[string]interface{}{"name": "UI"}},
+				"priority":        map[string]interface{}{"name": "High"},
+				"description":     adfDoc(`{"type":"paragraph","content":[{"type":"text","text":"Steps"}]}`),
+				"customfield_100": map[string]interface{}{"id": "1"},
+			},
+			expectMissing: []string{"customfield_200", "customfield_300", "customfield_400"},
+			expectOut: []string{
+				"warning: customfield_200 of PROJ-2 is not copied, it is not on the create screen in PROJ Task\n",
+				"warning: Removed option of PROJ-2 is not copied, \"9\" is not allowed in PROJ Task\n",
+				"created issue: https://jira-url/browse/PROJ-1\n  clones PROJ-2\n",
+			},
+		},
+		{
+			name:        "should keep values given by flags",
+			args:        "-s 'Login fails on iOS' --label ios --priority Low",
+			neverPrompt: true,
+			expectFields: map[string]interface{}{
+				"summary":  "Login fails on iOS",
+				"labels":   []interface{}{"web", "ios"},
+				"priority": map[string]interface{}{"name": "Low"},
+			},
+		},
+		{
+			name:    "should let user edit copied values",
+			choices: []string{"Summary: Login fails", "Labels: web", "Done"},
+			input:   "Login fails on iOS",
+			expectFields: map[string]interface{}{
+				"summary": "Login fails on iOS",
+			},
+			expectMissing: []string{"labels"},
+		},
+		{
+			name:    "should let user edit copied custom fields",
+			choices: []string{"Removed option: Removed", "Kept", "Browser: Safari", "None", "Done"},
+			expectFields: map[string]interface{}{
+				"customfield_300": map[string]interface{}{"id": "1"},
+			},
+			expectMissing: []string{"customfield_100"},
+			expectOut:     []string{"created issue: https://jira-url/browse/PROJ-1\n  clones PROJ-2\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			reg.Register(httpmock.REST("GET", "rest/api/3/issue/PROJ-2"), httpmock.JSONResponse(source))
+			reg.Register(
+				httpmock.REST("GET", "rest/api/3/myself"),
+				httpmock.JSONResponse(&jira.User{}),
+			)
+			// createmeta is loaded to copy custom fields and again to check required fields
+			for i := 0; i < 2; i++ {
+				issueTypesStub(reg, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(reg, "PROJ", "10001",
+					map[string]interface{}{"key": "summary", "name": "Summary", "required": true},
+					map[string]interface{}{"key": "description", "name": "Description"},
+					map[string]interface{}{"key": "assignee", "name": "Assignee"},
+					map[string]interface{}{"key": "labels", "name": "Labels"},
+					map[string]interface{}{"key": "components", "name": "Components",
+						"allowedValues": []map[string]interface{}{{"id": "7", "name": "UI"}}},
+					map[string]interface{}{"key": "priority", "name": "Priority",
+						"allowedValues": []map[string]interface{}{{"id": "2", "name": "High"}, {"id": "3", "name": "Low"}}},
+					map[string]interface{}{"key": "customfield_100", "name": "Browser",
+						"allowedValues": []map[string]interface{}{{"id": "1", "value": "Safari"}}},
+					map[string]interface{}{"key": "customfield_300", "name": "Removed option",
+						"allowedValues": []map[string]interface{}{{"id": "1", "value": "Kept"}}},
+				)
+			}
+			reg.Register(
+				httpmock.REST("GET", "rest/api/3/issueLinkType"),
+				httpmock.JSONResponse(map[string]interface{}{
+					"issueLinkTypes": []map[string]interface{}{
+						{"id": "4", "name": "Cloners", "inward": "is cloned by", "outward": "clones"},
+					},
+				}),
+			)
+			if !tt.neverPrompt {
+				noDuplicatesStub(reg)
+			}
+
+			var fields map[string]interface{}
+			reg.Register(
+				httpmock.REST("POST", "rest/api/3/issue"),
+				func(req *http.Request) (*http.Response, error) {
+					var body struct{ Fields map[string]interface{} }
+					_ = json.NewDecoder(req.Body).Decode(&body)
+					fields = body.Fields
+					return httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1"})(req)
+				},
+			)
+			var link string
+			reg.Register(
+				httpmock.REST("POST", "rest/api/3/issueLink"),
+				func(req *http.Request) (*http.Response, error) {
+					var body struct {
+						Type         struct{ Name string }
+						InwardIssue  struct{ Key string }
+						OutwardIssue struct{ Key string }
+					}
+					_ = json.NewDecoder(req.Body).Decode(&body)
+					link = body.Type.Name + " " + body.InwardIssue.Key + " " + body.OutwardIssue.Key
+					return httpmock.StatusStringResponse(201, "")(req)
+				},
+			)
+
+			choices := tt.choices
+			p := &prompt.PrompterMock{
+				SelectFunc: func(s string, options []string) (string, error) {
+					choice := choices[0]
+					choices = choices[1:]
+					assert.Contains(t, options, choice)
+					return choice, nil
+				},
+				InputFunc: func(s, def string, askOpts ...survey.AskOpt) (string, error) {
+					if s == "Labels" {
+						return "", nil
+					}
+					return tt.input, nil
+				},
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(tt.neverPrompt)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: p,
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split("--clone PROJ-2 " + tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			assert.NoError(t, err)
+			for k, v := range tt.expectFields {
+				assert.Equal(t, v, fields[k], k)
+			}
+			for _, k := range tt.expectMissing {
+				assert.NotContains(t, fields, k)
+			}
+			assert.Equal(t, "Cloners PROJ-1 PROJ-2", link)
+			for _, o := range tt.expectOut {
+				assert.Contains(t, out.String(), o)
+			}
+		})
+	}
+}
+
+func TestCreate_test_export(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		expectOut string
+		expectErr string
+	}{
+		{
+			name:      "should print key of created issue",
+			args:      "--jq .key",
+			expectOut: "PROJ-1\n",
+		},
+		{
+			name: "should print selected fields of created issue",
+			args: "--json=key,summary,labels,url",
+			expectOut: heredoc.Doc(`
+				{
+				  "key": "PROJ-1",
+				  "labels": [
+				    "deps"
+				  ],
+				  "summary": "Bump dependencies",
+				  "url": "https://jira-url/browse/PROJ-1"
+				}
+			`),
+		},
+		{
+			name: "should print fields set by jira",
+			args: "--json=status,reporter,assignee",
+			expectOut: heredoc.Doc(`
+				{
+				  "assignee": null,
+				  "reporter": {
+				    "accountId": "42",
+				    "displayName": "Jane Doe"
+				  },
+				  "status": {
+				    "name": "To Do"
+				  }
+				}
+			`),
+		},
+		{
+			name:      "should format created issue",
+			args:      "--output-template '{{.key}} {{truncate 8 .summary}}'",
+			expectOut: "PROJ-1 Bump ...",
+		},
+		{
+			name:      "should not export dry run",
+			args:      "--json --dry-run",
+			expectErr: "if any flags in the group [dry-run json] are set none of the others can be; [dry-run json] were all set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.expectErr == "" {
+				reg.Register(
+					httpmock.REST("GET", "rest/api/3/myself"),
+					httpmock.JSONResponse(&jira.User{}),
+				)
+				issueTypesStub(reg, "PROJ", jira.IssueType{ID: "10001", Name: "Task"})
+				createMetaFieldsStub(reg, "PROJ", "10001",
+					map[string]interface{}{"key": "summary", "name": "Summary", "required": true},
+					map[string]interface{}{"key": "labels", "name": "Labels"},
+					map[string]interface{}{"key": "assignee", "name": "Assignee"},
+				)
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue"),
+					httpmock.JSONResponse(&jira.Issue{Key: "PROJ-1", ID: "10001"}),
+				)
+				reg.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key": "PROJ-1", "id": "10001", "fields": {
+						"summary": "Bump dependencies", "labels": ["deps"], "assignee": null,
+						"reporter": {"accountId": "42", "displayName": "Jane Doe"}, "status": {"name": "To Do"}}}`),
+				)
+			}
+
+			out := &bytes.Buffer{}
+			io := &iostreams.IOStream{Out: out}
+			io.SetNeverPrompt(true)
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				Prompter: &prompt.PrompterMock{},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitclient.NewGitClientMock(), nil
+				},
+				IOStream: io,
+			}
+
+			argv, err := shlex.Split("-p PROJ -t Task -s 'Bump dependencies' -l deps " + tt.args)
+			assert.NoError(t, err)
+
+			// when
+			err = runCreateCommand(factory, argv...)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+		})
+	}
+}
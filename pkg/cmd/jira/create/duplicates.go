@@ -0,0 +1,141 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/config"
+)
+
+const (
+	// duplicateSearchLimit is a max number of possible duplicates shown
+	duplicateSearchLimit = 5
+	duplicateLinkType    = "Duplicate"
+
+	createAnyway       = "Create anyway"
+	openExistingPrefix = "Open "
+	linkPrefix         = "Create and link as duplicate of "
+)
+
+// errExistingIssueOpened stops creation when user decides to use existing issue
+var errExistingIssueOpened = errors.New("existing issue was opened instead of creating a new one")
+
+// shouldCheckDuplicates reports whether duplicates are searched before issue is created.
+// --check-duplicates wins, otherwise check is done in interactive mode only,
+// unless it is turned off in configuration.checkDuplicates.<project key>
+func shouldCheckDuplicates(ops *CreateOptions, cfg config.Config, projectKey string) bool {
+	if ops.CheckDuplicatesSet {
+		return ops.CheckDuplicates
+	}
+	if !ops.IOStream.CanPrompt() {
+		return false
+	}
+
+	value, _ := cfg.GetNested([]string{"configuration", "checkDuplicates", projectKey})
+	enabled, err := strconv.ParseBool(value)
+	return err != nil || enabled
+}
+
+// checkDuplicates searches open issues of the project with similar summary
+// and lets user decide what to do with them. It returns link to
+// the duplicated issue, when user wants the new issue to be linked to it.
+func checkDuplicates(jiraClient *jira.Client, ops *CreateOptions, issue *jira.Issue) (*jira.IssueLink, error) {
+	cfg, err := ops.Config()
+	if err != nil {
+		return nil, err
+	}
+	projectKey := issue.Fields.Project.Key
+	if !shouldCheckDuplicates(ops, cfg, projectKey) {
+		return nil, nil
+	}
+
+	duplicates, err := findDuplicates(jiraClient, projectKey, issue.Fields.Summary)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search for duplicates: %w", err)
+	}
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+
+	descriptions := make([]string, 0, len(duplicates))
+	for _, d := range duplicates {
+		descriptions = append(descriptions, d.Key+" "+d.Fields.Summary)
+	}
+
+	if ops.DryRun {
+		fmt.Fprintf(ops.Out, "\npossible duplicates:\n  %s\n", strings.Join(descriptions, "\n  "))
+		return nil, nil
+	}
+	if !ops.IOStream.CanPrompt() {
+		return nil, fmt.Errorf("possible duplicates found: %s. Use --check-duplicates=false to create issue anyway",
+			strings.Join(descriptions, ", "))
+	}
+
+	options := []string{createAnyway}
+	for _, d := range descriptions {
+		options = append(options, openExistingPrefix+d)
+	}
+	for _, d := range descriptions {
+		options = append(options, linkPrefix+d)
+	}
+
+	choice, err := ops.Prompter.Select("Possible duplicates found", options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(choice, openExistingPrefix):
+		key := strings.Fields(strings.TrimPrefix(choice, openExistingPrefix))[0]
+		url := fmt.Sprintf("%s%s%s", jiraClient.BaseURL, "browse/", key)
+		fmt.Fprintf(ops.Out, "\nopening %s\n", url)
+		if err = ops.Browser(url); err != nil {
+			return nil, err
+		}
+		return nil, errExistingIssueOpened
+	case strings.HasPrefix(choice, linkPrefix):
+		key := strings.Fields(strings.TrimPrefix(choice, linkPrefix))[0]
+		linkTypes, err := issues.GetLinkTypes(jiraClient)
+		if err != nil {
+			return nil, err
+		}
+		linkType, err := issues.FindLinkType(linkTypes, duplicateLinkType)
+		if err != nil {
+			return nil, err
+		}
+		return &jira.IssueLink{Type: *linkType, OutwardIssue: &jira.Issue{Key: key}}, nil
+	}
+
+	return nil, nil
+}
+
+// findDuplicates searches open issues of the project by words of summary
+func findDuplicates(jiraClient *jira.Client, projectKey, summary string) ([]jira.Issue, error) {
+	// characters reserved by jql text search are left out
+	words := strings.FieldsFunc(summary, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	jql := fmt.Sprintf("project = %s AND statusCategory != Done AND summary ~ %s ORDER BY updated DESC",
+		issues.QuoteJQL(projectKey), issues.QuoteJQL(strings.Join(words, " ")))
+
+	found, err := issues.Search(jiraClient, jql, []string{"summary"}, duplicateSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		if found[i].Fields == nil {
+			found[i].Fields = &jira.IssueFields{}
+		}
+	}
+
+	return found, nil
+}
@@ -2,28 +2,75 @@ package create
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/utils"
 )
 
+// FieldMeta describes a single field of createmeta response
+type FieldMeta struct {
+	FieldID         string         `json:"fieldId"`
+	Key             string         `json:"key"`
+	Name            string         `json:"name"`
+	Required        bool           `json:"required"`
+	HasDefaultValue bool           `json:"hasDefaultValue"`
+	Schema          FieldSchema    `json:"schema"`
+	AllowedValues   []AllowedValue `json:"allowedValues"`
+}
+
+type FieldSchema struct {
+	Type   string `json:"type"`
+	Items  string `json:"items"`
+	System string `json:"system"`
+	Custom string `json:"custom"`
+}
+
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Label returns human readable representation of allowed value
+func (v AllowedValue) Label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	if v.Value != "" {
+		return v.Value
+	}
+	return v.ID
+}
+
 type RequiredFieldsResult struct {
-	fields map[string]bool
-	err    error
+	// fields holds required fields only, keyed by field key
+	fields map[string]*FieldMeta
+	// allFields holds every field which can be set on create, keyed by field key
+	allFields map[string]*FieldMeta
+	err       error
 }
 
-func getRequiredFieldsResultAsync(c *jira.Client, p *Project, ch chan<- *RequiredFieldsResult) {
+// createMetaPageSize is a number of items requested per createmeta page
+const createMetaPageSize = 50
+
+func getRequiredFieldsResultAsync(c *jira.Client, projectKey string, issueType *jira.IssueType, ch chan<- *RequiredFieldsResult) {
 	go func() {
-		result := getRequiredFieldsResult(c, p)
+		result := getRequiredFieldsResult(c, projectKey, issueType)
 		ch <- result
 		close(ch)
 	}()
 }
 
-func getRequiredFieldsResult(jiraClient *jira.Client, project *Project) *RequiredFieldsResult {
-	fields, _, err := jiraClient.Issue.GetCreateMeta(context.Background(), &jira.GetQueryOptions{
-		Expand:      "projects.issuetypes.fields",
-		ProjectKeys: project.Key,
-	})
+func getRequiredFieldsResult(jiraClient *jira.Client, projectKey string, issueType *jira.IssueType) *RequiredFieldsResult {
+	fields, err := getCreateMetaFields(jiraClient, projectKey, issueType)
 	if err != nil {
 		return &RequiredFieldsResult{
 			fields: nil,
@@ -31,17 +78,256 @@ func getRequiredFieldsResult(jiraClient *jira.Client, project *Project) *Require
 		}
 	}
 
-	m := fields.Projects[0].IssueTypes[0].Fields
-	requiredFields := make(map[string]bool)
-	for k := range m {
-		isRequired, exists := m.Value(k + "/required")
-		if exists && isRequired == true {
-			requiredFields[k] = true
+	requiredFields := make(map[string]*FieldMeta)
+	for k, field := range fields {
+		if field.Required {
+			requiredFields[k] = field
 		}
 	}
 
 	return &RequiredFieldsResult{
-		fields: requiredFields,
-		err:    nil,
+		fields:    requiredFields,
+		allFields: fields,
+		err:       nil,
+	}
+}
+
+// getCreateMetaFields returns all fields available when creating an issue
+// of given type in given project, keyed by field key
+func getCreateMetaFields(jiraClient *jira.Client, projectKey string, issueType *jira.IssueType) (map[string]*FieldMeta, error) {
+	issueTypeID := issueType.ID
+	if issueTypeID == "" {
+		t, err := getCreateMetaIssueType(jiraClient, projectKey, issueType.Name)
+		if err != nil {
+			return nil, err
+		}
+		issueTypeID = t.ID
+	}
+
+	fields := make(map[string]*FieldMeta)
+	path := fmt.Sprintf("rest/api/3/issue/createmeta/%s/issuetypes/%s",
+		url.PathEscape(projectKey), url.PathEscape(issueTypeID))
+	for startAt := 0; ; {
+		page := &createMetaFieldsPage{}
+		if err := getCreateMetaPage(jiraClient, path, startAt, page); err != nil {
+			return nil, err
+		}
+
+		items := append(page.Fields, page.Values...)
+		for i := range items {
+			field := &items[i]
+			if field.Key == "" {
+				field.Key = field.FieldID
+			}
+			if field.Name == "" {
+				field.Name = field.Key
+			}
+			fields[field.Key] = field
+		}
+
+		startAt += len(items)
+		if len(items) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return fields, nil
+}
+
+// getCreateMetaIssueType finds issue type which can be created in project by its name
+func getCreateMetaIssueType(jiraClient *jira.Client, projectKey, name string) (*jira.IssueType, error) {
+	issueTypes, err := getCreateMetaIssueTypes(jiraClient, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	return findIssueType(&Project{Key: projectKey, IssueTypes: issueTypes}, name)
+}
+
+// getCreateMetaIssueTypes returns all issue types which can be created in project
+func getCreateMetaIssueTypes(jiraClient *jira.Client, projectKey string) ([]jira.IssueType, error) {
+	var issueTypes []jira.IssueType
+	path := fmt.Sprintf("rest/api/3/issue/createmeta/%s/issuetypes", url.PathEscape(projectKey))
+	for startAt := 0; ; {
+		page := &createMetaIssueTypesPage{}
+		if err := getCreateMetaPage(jiraClient, path, startAt, page); err != nil {
+			return nil, err
+		}
+
+		items := append(page.IssueTypes, page.Values...)
+		issueTypes = append(issueTypes, items...)
+
+		startAt += len(items)
+		if len(items) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return issueTypes, nil
+}
+
+// createmeta endpoints return items either in a named list or in "values"
+type createMetaFieldsPage struct {
+	Total  int         `json:"total"`
+	Fields []FieldMeta `json:"fields"`
+	Values []FieldMeta `json:"values"`
+}
+
+type createMetaIssueTypesPage struct {
+	Total      int              `json:"total"`
+	IssueTypes []jira.IssueType `json:"issueTypes"`
+	Values     []jira.IssueType `json:"values"`
+}
+
+func getCreateMetaPage(jiraClient *jira.Client, path string, startAt int, page interface{}) error {
+	values := url.Values{}
+	values.Set("startAt", strconv.Itoa(startAt))
+	values.Set("maxResults", strconv.Itoa(createMetaPageSize))
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet, path+"?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, page)
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+
+	return nil
+}
+
+// isRichTextField reports whether field value is expected in atlassian document format
+func isRichTextField(field *FieldMeta) bool {
+	return field.Schema.System == "description" ||
+		field.Schema.System == "environment" ||
+		field.Schema.Custom == "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+}
+
+// systemFields are filled from flags, defaults or previous prompts
+var systemFields = []string{"summary", "project", "issuetype", "reporter", "assignee"}
+
+// fieldsToInput returns required fields which were not provided yet,
+// fields with default value are left for jira to fill in
+func fieldsToInput(required map[string]*FieldMeta, ops *CreateOptions) []*FieldMeta {
+	provided := map[string]bool{
+		"description": ops.Description != "",
+		"labels":      len(ops.Labels) > 0,
+		"components":  len(ops.Components) > 0,
+		"priority":    ops.Priority != "",
+		"parent":      isSubtaskRequested(ops),
+	}
+
+	var result []*FieldMeta
+	for key, field := range required {
+		if ops.clone != nil && ops.clone.fields[key] != nil {
+			provided[key] = true
+		}
+		if utils.Contains(systemFields, key) || provided[key] || field.HasDefaultValue {
+			continue
+		}
+		result = append(result, field)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func fieldNames(fields []*FieldMeta) []string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// validateIssue checks issue against createmeta the same way jira does on create:
+// every field must be on the create screen, required fields must be set and
+// values must be among allowed ones
+func validateIssue(issue *jira.Issue, fields map[string]*FieldMeta) error {
+	data, err := json.Marshal(issue.Fields)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	var problems []string
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "project" || key == "issuetype" {
+			continue
+		}
+
+		field, ok := fields[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("field %s cannot be set, it is not on the create screen", key))
+			continue
+		}
+
+		if len(field.AllowedValues) == 0 {
+			continue
+		}
+		for _, v := range referencedValues(values[key]) {
+			if !isAllowed(field, v) {
+				problems = append(problems, fmt.Sprintf("%q is not allowed for %s", v, field.Name))
+			}
+		}
+	}
+
+	var missing []string
+	for key, field := range fields {
+		if _, ok := values[key]; !ok && field.Required && !field.HasDefaultValue &&
+			key != "project" && key != "issuetype" {
+			missing = append(missing, field.Name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		problems = append(problems, "missing required fields: "+strings.Join(missing, ", "))
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// referencedValues returns names, values or ids of objects referenced by field value,
+// e.g. {"name": "High"} or [{"id": "10"}]
+func referencedValues(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			result = append(result, referencedValues(item)...)
+		}
+		return result
+	case map[string]interface{}:
+		for _, k := range []string{"id", "name", "value"} {
+			if s, ok := v[k].(string); ok && s != "" {
+				return []string{s}
+			}
+		}
+	}
+	return nil
+}
+
+func isAllowed(field *FieldMeta, value string) bool {
+	for _, allowed := range field.AllowedValues {
+		if allowed.ID == value || strings.EqualFold(allowed.Name, value) || strings.EqualFold(allowed.Value, value) {
+			return true
+		}
 	}
+	return false
 }
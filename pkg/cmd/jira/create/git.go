@@ -0,0 +1,122 @@
+package create
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/stirboy/jh/pkg/config"
+)
+
+// headRevision is used when --from-commit is given without revision
+const headRevision = "HEAD"
+
+// branchIssueKeyRe matches issue key at the beginning of branch name, e.g. proj-12-
+var branchIssueKeyRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+[-_]*`)
+
+// prefillFromGit fills summary and description from commit or current branch,
+// values given by flags are kept
+func prefillFromGit(ops *CreateOptions, cfg config.Config) error {
+	if ops.FromCommit == "" && !ops.FromBranch {
+		return nil
+	}
+
+	gitClient, err := ops.GitClient()
+	if err != nil {
+		return err
+	}
+
+	var summary, description string
+	if ops.FromCommit != "" {
+		commit, err := gitClient.Commit(ops.FromCommit)
+		if err != nil {
+			return fmt.Errorf("cannot read commit: %w", err)
+		}
+
+		summary = commit.Subject
+		description = joinSections(commit.Body, changedFilesSection(commit.Files))
+	} else {
+		branch, err := gitClient.CurrentBranch()
+		if err != nil {
+			return fmt.Errorf("cannot read current branch: %w", err)
+		}
+		base, _ := cfg.GetNested([]string{"configuration", "git", "baseBranch"})
+
+		commits, err := gitClient.Log(branch, base)
+		if err != nil {
+			return fmt.Errorf("cannot read commits of branch %s: %w", branch, err)
+		}
+		files, err := gitClient.Diff(branch, base)
+		if err != nil {
+			return fmt.Errorf("cannot read changes of branch %s: %w", branch, err)
+		}
+
+		var commitsSection string
+		if len(commits) > 0 {
+			lines := []string{"Commits:", ""}
+			for _, c := range commits {
+				lines = append(lines, fmt.Sprintf("- %s (%s)", c.Subject, shortHash(c.Hash)))
+			}
+			commitsSection = strings.Join(lines, "\n")
+		}
+
+		summary = humanizeBranchName(branch)
+		description = joinSections(commitsSection, changedFilesSection(files))
+	}
+
+	ops.Summary = firstNonEmpty(ops.Summary, summary)
+	if ops.Description == "" && ops.DescriptionFile == "" {
+		// editor is opened with description prefilled
+		ops.Description = description
+	}
+
+	return nil
+}
+
+// humanizeBranchName turns e.g. feature/proj-12-fix-login_page into "Fix login page"
+func humanizeBranchName(branch string) string {
+	name := branchIssueKeyRe.ReplaceAllString(path.Base(branch), "")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	summary := []rune(strings.Join(words, " "))
+	summary[0] = unicode.ToUpper(summary[0])
+	return string(summary)
+}
+
+func changedFilesSection(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	lines := []string{"Changed files:", ""}
+	for _, f := range files {
+		lines = append(lines, fmt.Sprintf("- `%s`", f))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func joinSections(sections ...string) string {
+	var nonEmpty []string
+	for _, s := range sections {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n\n")
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,166 @@
+package create
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate_prefillFromGit(t *testing.T) {
+	tests := []struct {
+		name              string
+		ops               *CreateOptions
+		expectSummary     string
+		expectDescription string
+		expectBase        string
+	}{
+		{
+			name:          "should take summary and description from commit",
+			ops:           &CreateOptions{FromCommit: "HEAD~1"},
+			expectSummary: "Fix login",
+			expectDescription: heredoc.Doc(`
+				Session cookie was not refreshed.
+
+				Changed files:
+
+				- ` + "`auth/login.go`" + `
+				- ` + "`README.md`"),
+		},
+		{
+			name:              "should keep summary and description given by flags",
+			ops:               &CreateOptions{FromCommit: "HEAD", Summary: "Login fails", Description: "see logs"},
+			expectSummary:     "Login fails",
+			expectDescription: "see logs",
+		},
+		{
+			name:          "should take summary from branch and description from its commits",
+			ops:           &CreateOptions{FromBranch: true},
+			expectSummary: "Validate password length",
+			expectDescription: heredoc.Doc(`
+				Commits:
+
+				- Validate password (1a2b3c4)
+				- Add login form (5d6e7f8)
+
+				Changed files:
+
+				- ` + "`auth/password.go`"),
+			expectBase: "develop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CommitFunc = func(rev string) (*gitclient.Commit, error) {
+				assert.Equal(t, tt.ops.FromCommit, rev)
+				return &gitclient.Commit{
+					Subject: "Fix login",
+					Body:    "Session cookie was not refreshed.",
+					Files:   []string{"auth/login.go", "README.md"},
+				}, nil
+			}
+			gitClient.CurrentBranchFunc = func() (string, error) {
+				return "feature/proj-12-validate-password_length", nil
+			}
+			gitClient.LogFunc = func(branch, base string) ([]gitclient.Commit, error) {
+				assert.Equal(t, "feature/proj-12-validate-password_length", branch)
+				assert.Equal(t, tt.expectBase, base)
+				return []gitclient.Commit{
+					{Hash: "1a2b3c4d5e6f", Subject: "Validate password"},
+					{Hash: "5d6e7f8a9b0c", Subject: "Add login form"},
+				}, nil
+			}
+			gitClient.DiffFunc = func(branch, base string) ([]string, error) {
+				return []string{"auth/password.go"}, nil
+			}
+			tt.ops.GitClient = func() (gitclient.GitClient, error) {
+				return gitClient, nil
+			}
+
+			cfg := config.NewFromString("configuration:\n  git:\n    baseBranch: develop\n")
+
+			// when
+			err := prefillFromGit(tt.ops, cfg)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectSummary, tt.ops.Summary)
+			assert.Equal(t, tt.expectDescription, tt.ops.Description)
+		})
+	}
+}
+
+func TestCreate_humanizeBranchName(t *testing.T) {
+	assert.Equal(t, "Fix login page", humanizeBranchName("feature/PROJ-12-fix-login_page"))
+	assert.Equal(t, "Update deps", humanizeBranchName("update-deps"))
+	assert.Equal(t, "", humanizeBranchName("bugfix/proj-1"))
+}
+
+func TestCreate_fromCommitRevisionArgument(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expectRev string
+		expectErr string
+	}{
+		{
+			name:      "should default to HEAD",
+			args:      []string{"--from-commit"},
+			expectRev: "HEAD",
+			expectErr: "cannot read commit: stop",
+		},
+		{
+			name:      "should accept revision as argument",
+			args:      []string{"--from-commit", "HEAD~2"},
+			expectRev: "HEAD~2",
+			expectErr: "cannot read commit: stop",
+		},
+		{
+			name:      "should reject arguments without --from-commit",
+			args:      []string{"HEAD~2"},
+			expectErr: `unexpected argument "HEAD~2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var rev string
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CommitFunc = func(s string) (*gitclient.Commit, error) {
+				rev = s
+				return nil, errors.New("stop")
+			}
+
+			factory := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", nil)
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: &iostreams.IOStream{Out: &bytes.Buffer{}},
+			}
+
+			// when
+			err := runCreateCommand(factory, tt.args...)
+
+			// then
+			assert.EqualError(t, err, tt.expectErr)
+			assert.Equal(t, tt.expectRev, rev)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package create
+
+import (
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+)
+
+const (
+	blocksLinkType  = "Blocks"
+	relatesLinkType = "Relates"
+)
+
+// resolveLinks resolves link types of requested links. Links are described
+// as seen from the new issue, only the other issue of the link is set.
+func resolveLinks(jiraClient *jira.Client, ops *CreateOptions) ([]*jira.IssueLink, error) {
+	var cloned []string
+	if ops.clone != nil {
+		cloned = []string{ops.clone.key}
+	}
+	if len(ops.Blocks)+len(ops.BlockedBy)+len(ops.RelatesTo)+len(cloned) == 0 {
+		return nil, nil
+	}
+
+	linkTypes, err := issues.GetLinkTypes(jiraClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*jira.IssueLink
+	for _, l := range []struct {
+		typeName string
+		keys     []string
+		inward   bool
+	}{
+		{typeName: blocksLinkType, keys: ops.Blocks},
+		{typeName: blocksLinkType, keys: ops.BlockedBy, inward: true},
+		{typeName: relatesLinkType, keys: ops.RelatesTo},
+		{typeName: cloneLinkType, keys: cloned},
+	} {
+		if len(l.keys) == 0 {
+			continue
+		}
+
+		linkType, err := issues.FindLinkType(linkTypes, l.typeName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range l.keys {
+			link := &jira.IssueLink{Type: *linkType}
+			if l.inward {
+				link.InwardIssue = &jira.Issue{Key: key}
+			} else {
+				link.OutwardIssue = &jira.Issue{Key: key}
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// linkIssue creates links of the new issue, created links are added to its fields.
+// Failed link doesn't stop the others.
+func linkIssue(jiraClient *jira.Client, issue *jira.Issue, links []*jira.IssueLink) []error {
+	var errs []error
+	for _, link := range links {
+		var err error
+		if link.OutwardIssue != nil {
+			err = issues.CreateLink(jiraClient, &link.Type, issue.Key, link.OutwardIssue.Key)
+		} else {
+			err = issues.CreateLink(jiraClient, &link.Type, link.InwardIssue.Key, issue.Key)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot create link \"%s\": %w", issues.DescribeLink(link), err))
+			continue
+		}
+
+		issue.Fields.IssueLinks = append(issue.Fields.IssueLinks, link)
+	}
+
+	return errs
+}
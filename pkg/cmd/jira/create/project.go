@@ -3,6 +3,8 @@ package create
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strings"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 )
@@ -57,3 +59,34 @@ func getProjectResult(jiraClient *jira.Client) *ProjectResult {
 		err:           nil,
 	}
 }
+
+func getProject(jiraClient *jira.Client, key string) (*Project, error) {
+	req, err := jiraClient.NewRequest(context.Background(),
+		http.MethodGet, "rest/api/3/project/"+url.PathEscape(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	project := new(jira.Project)
+	resp, err := jiraClient.Do(req, project)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+
+	return &Project{
+		Key:        project.Key,
+		IssueTypes: project.IssueTypes,
+	}, nil
+}
+
+// findProject looks project up in recent projects first
+// and falls back to fetching it by key
+func findProject(jiraClient *jira.Client, projectKeyMap map[string]*Project, key string) (*Project, error) {
+	for k, p := range projectKeyMap {
+		if strings.EqualFold(k, key) {
+			return p, nil
+		}
+	}
+
+	return getProject(jiraClient, key)
+}
@@ -1,15 +1,24 @@
 package create
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
 	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
 	"github.com/stirboy/jh/pkg/utils"
+	"github.com/trivago/tgo/tcontainer"
 )
 
+// parentSearchLimit is a max number of issues offered as a parent
+const parentSearchLimit = 20
+
 func inputSummary(prompter prompt.Prompter) (string, error) {
 	summary, err := prompter.Input("Issue Summary", "Minor fixes", survey.WithValidator(survey.Required))
 	if err != nil {
@@ -34,12 +43,12 @@ func selectProject(prompter prompt.Prompter, projectKeyMap map[string]*Project)
 	return projectKeyMap[p], nil
 }
 
-func selectIssueType(prompter prompt.Prompter, project *Project) (*jira.IssueType, error) {
+// selectIssueType offers sub-task issue types when parent is set
+// and standard issue types otherwise
+func selectIssueType(prompter prompt.Prompter, project *Project, subtask bool) (*jira.IssueType, error) {
 	mapOfIssueTypes := make(map[string]*jira.IssueType)
 	for i := 0; i < len(project.IssueTypes); i++ {
-		if strings.ToLower(project.IssueTypes[i].Name) == "subtask" {
-			// let's skip creating subtask as it requires parent id
-			// we can always assign it to task in jira website after creation
+		if isSubtask(&project.IssueTypes[i]) != subtask {
 			continue
 		}
 		mapOfIssueTypes[project.IssueTypes[i].Name] = &project.IssueTypes[i]
@@ -49,6 +58,9 @@ func selectIssueType(prompter prompt.Prompter, project *Project) (*jira.IssueTyp
 	if err != nil {
 		return nil, err
 	}
+	if len(issueTypeKeys) == 0 && subtask {
+		return nil, fmt.Errorf("project %s has no sub-task issue types", project.Key)
+	}
 	sort.Strings(issueTypeKeys)
 
 	t, err := prompter.Select("Pick issue type", issueTypeKeys)
@@ -58,3 +70,212 @@ func selectIssueType(prompter prompt.Prompter, project *Project) (*jira.IssueTyp
 
 	return mapOfIssueTypes[t], nil
 }
+
+func isSubtask(issueType *jira.IssueType) bool {
+	name := strings.ToLower(issueType.Name)
+	return issueType.Subtask || name == "subtask" || name == "sub-task"
+}
+
+// checkSubtaskType makes sure that issue type given with parent is a sub-task type of project
+func checkSubtaskType(project *Project, issueType *jira.IssueType) error {
+	if isSubtask(issueType) {
+		return nil
+	}
+
+	var names []string
+	for i := range project.IssueTypes {
+		if isSubtask(&project.IssueTypes[i]) {
+			names = append(names, project.IssueTypes[i].Name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("issue type %s cannot have parent, project %s has no sub-task issue types", issueType.Name, project.Key)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("issue type %s cannot have parent, sub-task issue types of project %s: %s",
+		issueType.Name, project.Key, strings.Join(names, ", "))
+}
+
+// pickParent searches open issues of the project, which can be a parent of sub-task
+func pickParent(jiraClient *jira.Client, prompter prompt.Prompter, projectKey string) (string, error) {
+	query, err := prompter.Input("Search parent issue (leave empty for recently updated)", "")
+	if err != nil {
+		return "", err
+	}
+
+	jql := fmt.Sprintf("project = %s AND statusCategory != Done AND issuetype not in subTaskIssueTypes()",
+		issues.QuoteJQL(projectKey))
+	if query = strings.TrimSpace(query); query != "" {
+		jql += " AND text ~ " + issues.QuoteJQL(query)
+	}
+	jql += " ORDER BY updated DESC"
+
+	found, err := issues.Search(jiraClient, jql, []string{"summary"}, parentSearchLimit)
+	if err != nil {
+		return "", err
+	}
+	if len(found) == 0 {
+		return "", fmt.Errorf("no open issues found in project %s", projectKey)
+	}
+
+	options := make([]string, 0, len(found))
+	for _, issue := range found {
+		option := issue.Key
+		if issue.Fields != nil {
+			option += " " + issue.Fields.Summary
+		}
+		options = append(options, option)
+	}
+
+	choice, err := prompter.Select("Pick parent issue", options)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Fields(choice)[0], nil
+}
+
+func findIssueType(project *Project, name string) (*jira.IssueType, error) {
+	for i := 0; i < len(project.IssueTypes); i++ {
+		if strings.EqualFold(project.IssueTypes[i].Name, name) {
+			return &project.IssueTypes[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("issue type %q not found in project %s", name, project.Key)
+}
+
+// inputRequiredFields prompts for every required field which was not provided
+// and returns values in a form ready to be sent as issue fields
+func inputRequiredFields(jiraClient *jira.Client, ops *CreateOptions, required map[string]*FieldMeta) (tcontainer.MarshalMap, error) {
+	fields := fieldsToInput(required, ops)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	if !ops.IOStream.CanPrompt() {
+		return nil, fmt.Errorf("stdin is not a terminal, so jh cannot prompt for input. Missing required fields: %s",
+			strings.Join(fieldNames(fields), ", "))
+	}
+
+	values := tcontainer.NewMarshalMap()
+	for _, field := range fields {
+		v, err := inputField(jiraClient, ops.Prompter, field)
+		if err != nil {
+			return nil, err
+		}
+		values[field.Key] = v
+	}
+
+	return values, nil
+}
+
+func inputField(jiraClient *jira.Client, prompter prompt.Prompter, field *FieldMeta) (interface{}, error) {
+	isArray := field.Schema.Type == "array"
+	itemType := field.Schema.Type
+	if isArray {
+		itemType = field.Schema.Items
+	}
+
+	if len(field.AllowedValues) > 0 {
+		return selectAllowedValues(prompter, field, isArray)
+	}
+
+	if isRichTextField(field) {
+		text, err := prompter.Editor(field.Name, adf.EditorHint)
+		if err != nil {
+			return nil, err
+		}
+		return adf.FromMarkdown(adf.StripComments(text), users.NewMentionResolver(jiraClient)), nil
+	}
+
+	switch itemType {
+	case "user":
+		if isArray {
+			picked, err := users.PickUsers(jiraClient, prompter, field.Name)
+			if err != nil {
+				return nil, err
+			}
+			values := make([]map[string]string, 0, len(picked))
+			for _, u := range picked {
+				values = append(values, map[string]string{"accountId": u.AccountID})
+			}
+			return values, nil
+		}
+
+		u, err := users.PickUser(jiraClient, prompter, field.Name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"accountId": u.AccountID}, nil
+	case "number":
+		v, err := prompter.Input(field.Name, "", survey.WithValidator(prompt.NumberValidator))
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	case "date":
+		v, err := prompter.Input(field.Name+" (YYYY-MM-DD)", "", survey.WithValidator(prompt.DateValidator))
+		if err != nil {
+			return nil, err
+		}
+		return strings.TrimSpace(v), nil
+	case "string":
+		v, err := prompter.Input(field.Name, "", survey.WithValidator(survey.Required))
+		if err != nil {
+			return nil, err
+		}
+		if isArray {
+			return splitList(v), nil
+		}
+		return v, nil
+	case "option":
+		v, err := prompter.Input(field.Name, "", survey.WithValidator(survey.Required))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"value": v}, nil
+	}
+
+	return nil, fmt.Errorf("required field %q of type %q is not supported yet, please set a default value for it in jira",
+		field.Name, field.Schema.Type)
+}
+
+func selectAllowedValues(prompter prompt.Prompter, field *FieldMeta, multiple bool) (interface{}, error) {
+	options := make([]string, 0, len(field.AllowedValues))
+	byLabel := make(map[string]AllowedValue)
+	for _, v := range field.AllowedValues {
+		options = append(options, v.Label())
+		byLabel[v.Label()] = v
+	}
+
+	if multiple {
+		chosen, err := prompter.MultiSelect("Pick "+field.Name, options)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]map[string]string, 0, len(chosen))
+		for _, c := range chosen {
+			values = append(values, map[string]string{"id": byLabel[c].ID})
+		}
+		return values, nil
+	}
+
+	chosen, err := prompter.Select("Pick "+field.Name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"id": byLabel[chosen].ID}, nil
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
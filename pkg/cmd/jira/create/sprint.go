@@ -0,0 +1,180 @@
+package create
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/agile"
+)
+
+const (
+	activeSprint = "active"
+	nextSprint   = "next"
+)
+
+// resolveSprint finds sprint requested by --sprint on the board of the project,
+// it returns nil when no sprint is requested
+func resolveSprint(jiraClient *jira.Client, ops *CreateOptions, projectKey string) (*agile.Sprint, error) {
+	if ops.Sprint == "" {
+		return nil, nil
+	}
+
+	boardID, err := resolveBoard(jiraClient, ops, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(ops.Sprint) {
+	case activeSprint:
+		sprints, err := agile.GetSprints(jiraClient, boardID, "active")
+		if err != nil {
+			return nil, err
+		}
+		if len(sprints) == 0 {
+			return nil, fmt.Errorf("board %d has no active sprint", boardID)
+		}
+		if len(sprints) == 1 {
+			return &sprints[0], nil
+		}
+		// boards can run parallel sprints
+		return selectSprint(ops, sprints)
+	case nextSprint:
+		sprints, err := agile.GetSprints(jiraClient, boardID, "future")
+		if err != nil {
+			return nil, err
+		}
+		if len(sprints) == 0 {
+			return nil, fmt.Errorf("board %d has no future sprint", boardID)
+		}
+		return &sprints[0], nil
+	}
+
+	sprints, err := agile.GetSprints(jiraClient, boardID, "active", "future")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(sprints))
+	for i := range sprints {
+		if strings.EqualFold(sprints[i].Name, ops.Sprint) {
+			return &sprints[i], nil
+		}
+		names = append(names, sprints[i].Name)
+	}
+
+	return nil, fmt.Errorf("sprint %q not found on board %d, open sprints are: %s",
+		ops.Sprint, boardID, strings.Join(names, ", "))
+}
+
+func selectSprint(ops *CreateOptions, sprints []agile.Sprint) (*agile.Sprint, error) {
+	names := make([]string, 0, len(sprints))
+	for _, s := range sprints {
+		names = append(names, s.Name)
+	}
+
+	if !ops.IOStream.CanPrompt() {
+		return nil, fmt.Errorf("there are %d active sprints, use --sprint with one of: %s",
+			len(sprints), strings.Join(names, ", "))
+	}
+
+	name, err := ops.Prompter.Select("Pick sprint", names)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sprints {
+		if sprints[i].Name == name {
+			return &sprints[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("sprint %q not found", name)
+}
+
+// resolveBoard returns id of the scrum board of the project. Board is looked up
+// once and remembered in configuration.boards.<project key>
+func resolveBoard(jiraClient *jira.Client, ops *CreateOptions, projectKey string) (int, error) {
+	cfg, err := ops.Config()
+	if err != nil {
+		return 0, err
+	}
+
+	keys := []string{"configuration", "boards", projectKey}
+	if value, _ := cfg.GetNested(keys); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid board id %q in configuration.boards.%s", value, projectKey)
+		}
+		return id, nil
+	}
+
+	boards, err := agile.GetScrumBoards(jiraClient, projectKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var board *agile.Board
+	switch {
+	case len(boards) == 0:
+		return 0, fmt.Errorf("project %s has no scrum board, so issues can't be added to a sprint", projectKey)
+	case len(boards) == 1:
+		board = &boards[0]
+	default:
+		board, err = selectBoard(ops, projectKey, boards)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if !ops.DryRun {
+		cfg.SetNested(keys, strconv.Itoa(board.ID))
+		if err := cfg.Write(); err != nil {
+			fmt.Fprintf(ops.Out, "Unable to remember board of project %s - %s\n", projectKey, err.Error())
+		}
+	}
+
+	return board.ID, nil
+}
+
+func selectBoard(ops *CreateOptions, projectKey string, boards []agile.Board) (*agile.Board, error) {
+	options := make([]string, 0, len(boards))
+	for _, b := range boards {
+		options = append(options, fmt.Sprintf("%d %s", b.ID, b.Name))
+	}
+
+	if !ops.IOStream.CanPrompt() {
+		return nil, fmt.Errorf("project %s has %d scrum boards, choose one with `jh config set configuration.boards.%s <id>`: %s",
+			projectKey, len(boards), projectKey, strings.Join(options, ", "))
+	}
+
+	option, err := ops.Prompter.Select(fmt.Sprintf("Pick board of project %s", projectKey), options)
+	if err != nil {
+		return nil, err
+	}
+	for i, o := range options {
+		if o == option {
+			return &boards[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("board %q not found", option)
+}
+
+// planIssue sets epic of the created issue and moves it into the sprint,
+// both are tried even if one of them fails
+func planIssue(jiraClient *jira.Client, ops *CreateOptions, issueKey string, sprint *agile.Sprint) []error {
+	var errs []error
+	if ops.Epic != "" {
+		if err := agile.MoveIssuesToEpic(jiraClient, ops.Epic, issueKey); err != nil {
+			errs = append(errs, fmt.Errorf("cannot add issue to epic %s: %w", ops.Epic, err))
+		}
+	}
+
+	if sprint != nil {
+		if err := agile.MoveIssuesToSprint(jiraClient, sprint.ID, issueKey); err != nil {
+			errs = append(errs, fmt.Errorf("cannot move issue to sprint %s: %w", sprint.Name, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,190 @@
+package create
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Template presets values of a new issue.
+// Summary and description can contain {{placeholders}} which are filled at runtime.
+type Template struct {
+	Project     string   `yaml:"project"`
+	IssueType   string   `yaml:"issueType"`
+	Summary     string   `yaml:"summary"`
+	Labels      []string `yaml:"labels"`
+	Components  []string `yaml:"components"`
+	Priority    string   `yaml:"priority"`
+	Description string   `yaml:"description"`
+}
+
+// repoTemplatesDir is a directory inside git repository with templates, one per file
+var repoTemplatesDir = filepath.Join(".jh", "templates")
+
+var placeholderRe = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// loadTemplate finds template by name. Templates of current repository
+// take precedence over templates from configuration.templates in config.yml
+func loadTemplate(ops *CreateOptions, cfg config.Config, name string) (*Template, error) {
+	repoTemplates, err := readRepoTemplates(ops)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := repoTemplates[name]; ok {
+		return t, nil
+	}
+
+	cfgTemplates := map[string]*Template{}
+	err = cfg.Decode([]string{"configuration", "templates"}, &cfgTemplates)
+	var notFound config.KeyNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("cannot read templates from config: %w", err)
+	}
+	if t, ok := cfgTemplates[name]; ok && t != nil {
+		return t, nil
+	}
+
+	names := make(map[string]bool)
+	for n := range repoTemplates {
+		names[n] = true
+	}
+	for n := range cfgTemplates {
+		names[n] = true
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("template %q not found, no templates are configured", name)
+	}
+
+	available := make([]string, 0, len(names))
+	for n := range names {
+		available = append(available, n)
+	}
+	sort.Strings(available)
+
+	return nil, fmt.Errorf("template %q not found, available templates: %s", name, strings.Join(available, ", "))
+}
+
+// readRepoTemplates reads templates from .jh/templates/*.yml of current git repository.
+// Nothing is returned when current directory is not a git repository.
+func readRepoTemplates(ops *CreateOptions) (map[string]*Template, error) {
+	templates := make(map[string]*Template)
+
+	gitClient, err := ops.GitClient()
+	if err != nil {
+		return templates, nil
+	}
+	root, err := gitClient.RootDir()
+	if err != nil {
+		return templates, nil
+	}
+
+	dir := filepath.Join(root, repoTemplatesDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return templates, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		t := &Template{}
+		if err = yaml.Unmarshal(data, t); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", filepath.Join(repoTemplatesDir, e.Name()), err)
+		}
+		templates[strings.TrimSuffix(e.Name(), ext)] = t
+	}
+
+	return templates, nil
+}
+
+// applyTemplate merges template into options. Values given by flags win over
+// the template, the template wins over defaults stored in config.
+// Labels and components of template and flags are combined.
+func applyTemplate(ops *CreateOptions, t *Template) error {
+	if err := fillPlaceholders(ops, t); err != nil {
+		return err
+	}
+
+	ops.ProjectKey = firstNonEmpty(ops.ProjectKey, t.Project)
+	ops.IssueTypeName = firstNonEmpty(ops.IssueTypeName, t.IssueType)
+	ops.Summary = firstNonEmpty(ops.Summary, t.Summary)
+	ops.Priority = firstNonEmpty(ops.Priority, t.Priority)
+	ops.Labels = mergeValues(t.Labels, ops.Labels)
+	ops.Components = mergeValues(t.Components, ops.Components)
+
+	if ops.DescriptionFile == "" {
+		ops.Description = firstNonEmpty(ops.Description, t.Description)
+	}
+
+	return nil
+}
+
+// fillPlaceholders replaces {{placeholders}} in template summary and description
+// with values given by --var flags or asks for them
+func fillPlaceholders(ops *CreateOptions, t *Template) error {
+	var names []string
+	for _, text := range []string{t.Summary, t.Description} {
+		for _, m := range placeholderRe.FindAllStringSubmatch(text, -1) {
+			if _, ok := ops.TemplateVars[m[1]]; !ok && !utils.Contains(names, m[1]) {
+				names = append(names, m[1])
+			}
+		}
+	}
+
+	values := make(map[string]string, len(ops.TemplateVars)+len(names))
+	for k, v := range ops.TemplateVars {
+		values[k] = v
+	}
+
+	if len(names) > 0 && !ops.IOStream.CanPrompt() {
+		return fmt.Errorf("stdin is not a terminal, so jh cannot prompt for input. Missing template values: %s (use --var name=value)",
+			strings.Join(names, ", "))
+	}
+
+	for _, name := range names {
+		v, err := ops.Prompter.Input(name, "")
+		if err != nil {
+			return err
+		}
+		values[name] = v
+	}
+
+	replace := func(s string) string {
+		return placeholderRe.ReplaceAllStringFunc(s, func(p string) string {
+			return values[placeholderRe.FindStringSubmatch(p)[1]]
+		})
+	}
+	t.Summary = replace(t.Summary)
+	t.Description = replace(t.Description)
+
+	return nil
+}
+
+func mergeValues(a, b []string) []string {
+	var result []string
+	for _, v := range append(append([]string{}, a...), b...) {
+		if !utils.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
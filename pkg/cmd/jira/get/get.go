@@ -1,36 +1,153 @@
 package get
 
 import (
-	"context"
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/export"
 	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stirboy/jh/pkg/utils"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
 )
 
 type GetOptions struct {
-	JiraIssueKey string
-	JiraClient   func() (*jira.Client, error)
+	// JiraIssueKeys are shown in given order
+	JiraIssueKeys []string
+	JiraClient    func() (*jira.Client, error)
+	IOStream      *iostreams.IOStream
+	// KeyResolver finds issue key in current branch, when it is not given
+	KeyResolver *issues.KeyResolver
+
+	// Comments is a number of latest comments shown, all when it is not positive
+	Comments int
+	// History shows changes of issue fields
+	History bool
+	// Transitions shows transitions available from current status
+	Transitions bool
+	// Compact shows issues as short cards, it is used for several issues
+	// unless comments, history or transitions are requested
+	Compact bool
+
+	// Exporter prints issue as json, as it is returned by jira api unless fields
+	// are given, or formats it by jq expression or go template
+	Exporter export.Options
+	// exportArray exports issues as json array, even a single one
+	exportArray bool
 }
 
+// maxConcurrentRequests limits number of issues fetched at once
+const maxConcurrentRequests = 5
+
 func NewGetCmd(f *factory.Factory) *cobra.Command {
+	ops := &GetOptions{
+		JiraClient:  f.JiraClient,
+		IOStream:    f.IOStream,
+		KeyResolver: issues.NewKeyResolver(f),
+	}
+
 	cmd := &cobra.Command{
-		Use:   "get <jira-key>",
-		Short: "get jira issue",
-		Args:  cobra.ExactArgs(1),
+		Use:   "get [<jira-key>...]",
+		Short: "get jira issues",
+		Long: heredoc.Doc(`
+			Show jira issue: its status, assignee, priority, labels, sprint,
+			parent and epic, description and latest comments. Field changes and
+			transitions available from current status are shown on request.
+
+			Without key, issue key is taken from current git branch, e.g. feature/proj-123/login,
+			by configuration.git.keyPattern regular expression, its first matched group is the key.
+			When branch has no key, or its issue is not found, issue is picked among recent issues.
+
+			Several issues are shown as compact cards in given order, or exported as json array.
+			Keys are also read from standard input given as -, or piped when no key is given,
+			one per line, only the first column is used, so that output of other commands can
+			be piped. Issues that cannot be fetched are reported at the end, others are shown anyway.
+		`),
+		Example: heredoc.Doc(`
+			$ jh get PROJ-1
+
+			# issue of current branch
+			$ jh get
+			$ jh config set configuration.git.keyPattern '^[a-z]+/([a-z]+-[0-9]+)'
 
+			# all comments, field changes and available transitions
+			$ jh get PROJ-1 --comments --history --transitions
+			$ jh get PROJ-1 --comments 10
+
+			# several issues, keys given as arguments or on standard input
+			$ jh get PROJ-1 PROJ-2 PROJ-3
+			$ cat keys.txt | jh get
+			$ cat keys.txt | jh get - --json=key,status
+
+			# issue as returned by jira api
+			$ jh get PROJ-1 --json
+
+			# selected fields, key, id, self, url and with --history or --transitions also
+			# changelog and transitions are available along with jira fields
+			$ jh get PROJ-1 --json=key,summary,status,customfield_10020
+
+			# filter json with built-in jq or format it with go template
+			$ jh get PROJ-1 --jq '.fields.labels | join(",")'
+			$ jh get PROJ-1 --json=key,summary,updated --template '{{.key}} {{truncate 40 .summary}} {{timeago .updated}}'
+		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ops := &GetOptions{
-				JiraIssueKey: args[0],
-				JiraClient:   f.JiraClient,
+			// --comments has optional value, so number of comments can be given as a separate argument
+			if n := len(args); n > 0 && cmd.Flags().Changed("comments") && ops.Comments == 0 {
+				if count, err := strconv.Atoi(args[n-1]); err == nil {
+					ops.Comments = count
+					args = args[:n-1]
+				}
+			}
+
+			// stdin of scripts and git hooks is not a terminal either, so without arguments
+			// keys are read only when they are piped, otherwise key is taken from branch
+			readStdin := len(args) == 1 && args[0] == "-" ||
+				len(args) == 0 && ops.IOStream.IsInPiped() && ops.IOStream.In != nil
+			if readStdin {
+				keys, err := readKeys(ops.IOStream.In)
+				if err != nil {
+					return fmt.Errorf("cannot read issue keys: %w", err)
+				}
+				if len(keys) == 0 && len(args) > 0 {
+					return errors.New("no issue keys given on standard input")
+				}
+				args = keys
+				ops.exportArray = len(keys) > 0
+			}
+
+			if len(args) == 0 {
+				key, err := ops.KeyResolver.Resolve(args)
+				if err != nil {
+					return err
+				}
+				args = []string{key}
 			}
+
+			ops.JiraIssueKeys = uniqueKeys(args)
+			ops.exportArray = ops.exportArray || len(ops.JiraIssueKeys) > 1
+			ops.Compact = len(ops.JiraIssueKeys) > 1 &&
+				!cmd.Flags().Changed("comments") && !ops.History && !ops.Transitions
 			return run(ops)
 		},
 	}
 
+	cmd.Flags().IntVar(&ops.Comments, "comments", latestComments, "Show `N` latest comments, all when N is not given")
+	cmd.Flags().Lookup("comments").NoOptDefVal = "0"
+	cmd.Flags().BoolVar(&ops.History, "history", false, "Show changes of issue fields")
+	cmd.Flags().BoolVar(&ops.Transitions, "transitions", false, "Show transitions available from current status")
+	export.AddFlags(cmd, &ops.Exporter, export.TemplateFlag)
+
 	return cmd
 }
 
@@ -40,16 +157,179 @@ func run(ops *GetOptions) error {
 		return err
 	}
 
-	issue, _, err := jiraClient.Issue.Get(context.Background(), ops.JiraIssueKey, &jira.GetQueryOptions{})
-	if err != nil {
-		panic(err)
+	results := fetchIssues(jiraClient, ops.JiraIssueKeys, issueQuery(ops))
+	baseURL := jiraClient.BaseURL.String()
+
+	var failed []issueResult
+	var exported []interface{}
+	cs := ops.IOStream.ColorScheme()
+	shown := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+			continue
+		}
+
+		if ops.Exporter.Enabled() {
+			data, err := exportedData(r.raw, baseURL, ops.Exporter.Fields)
+			if err != nil {
+				failed = append(failed, issueResult{key: r.key, err: fmt.Errorf("cannot read issue %s: %w", r.key, err)})
+				continue
+			}
+			exported = append(exported, data)
+			continue
+		}
+
+		view := &issueView{}
+		if err = json.Unmarshal(r.raw, view); err != nil {
+			failed = append(failed, issueResult{key: r.key, err: fmt.Errorf("cannot read issue %s: %w", r.key, err)})
+			continue
+		}
+
+		if shown > 0 {
+			fmt.Fprintln(ops.IOStream.Out)
+		}
+		shown++
+		browseURL := fmt.Sprintf("%sbrowse/%s", baseURL, view.Key)
+		if ops.Compact {
+			printCompactIssue(ops.IOStream.Out, cs, view, browseURL)
+			continue
+		}
+		printIssue(ops.IOStream.Out, cs, view, browseURL, sections{
+			comments:    ops.Comments,
+			history:     ops.History,
+			transitions: ops.Transitions,
+		})
 	}
 
-	bytes, err := json.MarshalIndent(issue, "", "  ")
-	if err != nil {
-		return err
+	if ops.Exporter.Enabled() {
+		switch {
+		case ops.exportArray:
+			if exported == nil {
+				exported = []interface{}{}
+			}
+			if err := ops.Exporter.Write(ops.IOStream, exported); err != nil {
+				return err
+			}
+		case len(exported) == 1:
+			if err := ops.Exporter.Write(ops.IOStream, exported[0]); err != nil {
+				return err
+			}
+		}
 	}
 
-	fmt.Println(string(bytes))
-	return nil
+	return fetchError(failed, len(results))
+}
+
+type issueResult struct {
+	key string
+	raw json.RawMessage
+	err error
+}
+
+// fetchIssues gets issues concurrently, at most maxConcurrentRequests at once,
+// results are in order of keys
+func fetchIssues(jiraClient *jira.Client, keys []string, query url.Values) []issueResult {
+	results := make([]issueResult, len(keys))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < maxConcurrentRequests && w < len(keys); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				raw, err := issues.GetRaw(jiraClient, keys[i], query)
+				results[i] = issueResult{key: keys[i], raw: raw, err: err}
+			}
+		}()
+	}
+	for i := range keys {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// fetchError reports issues that cannot be shown, a single issue error is returned as it is
+func fetchError(failed []issueResult, total int) error {
+	switch {
+	case len(failed) == 0:
+		return nil
+	case total == 1:
+		return failed[0].err
+	}
+
+	msg := fmt.Sprintf("cannot get %d of %d issues:", len(failed), total)
+	for _, r := range failed {
+		msg += "\n  " + r.err.Error()
+	}
+	return errors.New(msg)
+}
+
+// exportedData returns issue as it is returned by jira api,
+// or flattened issue when fields are selected
+func exportedData(raw json.RawMessage, baseURL string, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		var data interface{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	return issues.ExportedIssue(raw, baseURL)
+}
+
+// readKeys reads issue keys, one per line, other columns, e.g. issue summary, are ignored
+func readKeys(r io.Reader) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		keys = append(keys, fields[0])
+	}
+	return keys, scanner.Err()
+}
+
+// uniqueKeys uppercases keys and drops repeated ones, order is kept
+func uniqueKeys(keys []string) []string {
+	var result []string
+	for _, k := range keys {
+		k = strings.ToUpper(k)
+		if !utils.Contains(result, k) {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+// issueQuery returns fields and expand options of issue request
+func issueQuery(ops *GetOptions) url.Values {
+	var expand []string
+	if ops.History {
+		expand = append(expand, "changelog")
+	}
+	if ops.Transitions {
+		expand = append(expand, "transitions")
+	}
+
+	query := url.Values{}
+	switch fields := issues.JiraFields(ops.Exporter.Fields); {
+	case len(fields) > 0:
+		query.Set("fields", strings.Join(fields, ","))
+	case !ops.Exporter.Enabled():
+		// card shows navigable fields and comments, schema is needed
+		// to find sprint and epic link among custom fields
+		query.Set("fields", "*navigable,comment")
+		expand = append(expand, "schema")
+	}
+	if len(expand) > 0 {
+		query.Set("expand", strings.Join(expand, ","))
+	}
+	return query
 }
@@ -0,0 +1,490 @@
+package get
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+const issueJSON = `{
+	"key": "PROJ-1",
+	"fields": {
+		"summary": "Login fails on Safari",
+		"issuetype": {"name": "Bug"},
+		"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+		"assignee": {"displayName": "Jane Doe"},
+		"priority": {"name": "High"},
+		"labels": ["web", "safari"],
+		"parent": {"key": "PROJ-0", "fields": {"summary": "Auth rework", "issuetype": {"name": "Epic"}}},
+		"customfield_10020": [
+			{"name": "Sprint 11", "state": "closed"},
+			{"name": "Sprint 12", "state": "active"}
+		],
+		"customfield_10030": null,
+		"description": {"type": "doc", "version": 1, "content": [
+			{"type": "paragraph", "content": [
+				{"type": "text", "text": "Login "},
+				{"type": "text", "text": "fails", "marks": [{"type": "strong"}]}
+			]},
+			{"type": "bulletList", "content": [
+				{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "open safari"}]}]}
+			]}
+		]},
+		"comment": {"total": 4, "comments": [
+			{"author": {"displayName": "A"}, "created": "2022-11-01T10:00:00.000+0000",
+				"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "first"}]}]}},
+			{"author": {"displayName": "B"}, "created": "2022-11-02T10:00:00.000+0000",
+				"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "second"}]}]}},
+			{"author": {"displayName": "C"}, "created": "2022-11-03T10:00:00.000+0000",
+				"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "third"}]}]}},
+			{"author": {"displayName": "D"}, "created": "2022-11-04T10:00:00.000+0000",
+				"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "fourth"}]}]}}
+		]}
+	},
+	"schema": {
+		"customfield_10020": {"custom": "com.pyxis.greenhopper.jira:gh-sprint"},
+		"customfield_10030": {"custom": "com.pyxis.greenhopper.jira:gh-epic-link"}
+	}
+}`
+
+// TestMain fixes local time zone, times of issues are printed in it
+func TestMain(m *testing.M) {
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		args  []string
+		stdin string
+		// piped stdin is read when no key is given, otherwise it is a terminal or e.g. loop input
+		piped     bool
+		stub      func(r *httpmock.Registry)
+		expectOut string
+		expectErr string
+	}{
+		{
+			name: "should print issue card",
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"expand": []string{"schema"}}),
+					httpmock.StringResponse(issueJSON),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				PROJ-1 Login fails on Safari
+				Status:   In Progress
+				Type:     Bug
+				Assignee: Jane Doe
+				Priority: High
+				Labels:   web, safari
+				Sprint:   Sprint 12
+				Epic:     PROJ-0 Auth rework
+
+				Login fails
+
+				• open safari
+
+				Latest 3 of 4 comments
+
+				B 2022-11-02 10:00
+				│ second
+
+				C 2022-11-03 10:00
+				│ third
+
+				D 2022-11-04 10:00
+				│ fourth
+
+				View this issue on Jira: https://jira-url/browse/PROJ-1
+			`),
+		},
+		{
+			name: "should print all comments, history and transitions",
+			key:  "PROJ-3",
+			args: []string{"--comments", "--history", "--transitions"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-3", url.Values{
+						"fields": []string{"*navigable,comment"},
+						"expand": []string{"changelog,transitions,schema"},
+					}),
+					httpmock.StringResponse(`{
+						"key": "PROJ-3",
+						"fields": {
+							"summary": "Flaky test",
+							"issuetype": {"name": "Task"},
+							"status": {"name": "To Do", "statusCategory": {"key": "new"}},
+							"comment": {"total": 3, "comments": [
+								{"id": "1", "author": {"displayName": "A"}, "created": "2022-11-01T10:00:00.000+0000",
+									"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Fails on CI"}]}]}},
+								{"id": "2", "author": {"displayName": "B"}, "created": "2022-11-02T10:00:00.000+0000",
+									"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Which job?"}]}]}},
+								{"id": "3", "parentId": 1, "author": {"displayName": "C"}, "created": "2022-11-03T10:00:00.000+0000",
+									"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Confirmed"}]}]}}
+							]}
+						},
+						"changelog": {"total": 2, "histories": [
+							{"author": {"displayName": "A"}, "created": "2022-11-01T09:00:00.000+0000", "items": [
+								{"field": "status", "fromString": "Backlog", "toString": "To Do"}
+							]},
+							{"author": {"displayName": "B"}, "created": "2022-11-02T09:00:00.000+0000", "items": [
+								{"field": "assignee", "fromString": null, "toString": "B"},
+								{"field": "labels", "fromString": "", "toString": "ci flaky"}
+							]}
+						]},
+						"transitions": [
+							{"name": "Start progress", "to": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}},
+							{"name": "Done", "to": {"name": "Done", "statusCategory": {"key": "done"}}}
+						]
+					}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				PROJ-3 Flaky test
+				Status:   To Do
+				Type:     Task
+				Assignee: Unassigned
+
+				No description provided
+
+				Comments
+
+				A 2022-11-01 10:00
+				│ Fails on CI
+
+				    C 2022-11-03 10:00
+				    │ Confirmed
+
+				B 2022-11-02 10:00
+				│ Which job?
+
+				History
+
+				2022-11-02 09:00 B
+				  assignee: none → B
+				  labels: none → ci flaky
+
+				2022-11-01 09:00 A
+				  status: Backlog → To Do
+
+				Transitions from To Do
+				  Start progress → In Progress
+				  Done
+
+				View this issue on Jira: https://jira-url/browse/PROJ-3
+			`),
+		},
+		{
+			name: "should accept number of comments as separate argument",
+			args: []string{"--comments", "1", "--jq", ".fields.comment.comments | length"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(issueJSON),
+				)
+			},
+			expectOut: "4\n",
+		},
+		{
+			name: "should print minimal issue card",
+			key:  "PROJ-2",
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-2"),
+					httpmock.StringResponse(`{"key": "PROJ-2", "fields": {"summary": "Empty", "issuetype": {"name": "Task"},
+						"parent": {"key": "PROJ-1", "fields": {"summary": "Story", "issuetype": {"name": "Story"}}}}}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				PROJ-2 Empty
+				Type:     Task
+				Assignee: Unassigned
+				Parent:   PROJ-1 Story
+
+				No description provided
+
+				View this issue on Jira: https://jira-url/browse/PROJ-2
+			`),
+		},
+		{
+			name: "should get issue of current branch",
+			key:  "-",
+			args: []string{"--jq", ".key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"fields": []string{"summary"}}), httpmock.StringResponse(`{"key": "PROJ-1"}`))
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key":"PROJ-1","fields":{"summary":"Login"}}`),
+				)
+			},
+			expectOut: "PROJ-1\n",
+		},
+		{
+			name:  "should get issue of current branch when stdin is not piped",
+			key:   "-",
+			stdin: "PROJ-9 ref of pre-push hook\n",
+			args:  []string{"--jq", ".key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"fields": []string{"summary"}}), httpmock.StringResponse(`{"key": "PROJ-1"}`))
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key":"PROJ-1","fields":{"summary":"Login"}}`),
+				)
+			},
+			expectOut: "PROJ-1\n",
+		},
+		{
+			name:  "should read keys from stdin given as -",
+			key:   "-",
+			stdin: "PROJ-2\n",
+			args:  []string{"-", "--jq", ".[].key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-2"),
+					httpmock.StringResponse(`{"key":"PROJ-2","fields":{"summary":"Empty"}}`),
+				)
+			},
+			expectOut: "PROJ-2\n",
+		},
+		{
+			name: "should print raw issue",
+			args: []string{"--json"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key":"PROJ-1","fields":{"summary":"Login"}}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				{
+				  "fields": {
+				    "summary": "Login"
+				  },
+				  "key": "PROJ-1"
+				}
+			`),
+		},
+		{
+			name: "should print selected fields",
+			args: []string{"--json=key,url,summary,assignee"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"fields": []string{"summary,assignee"}}),
+					httpmock.StringResponse(`{"key":"PROJ-1","id":"10001","fields":{"summary":"Login","assignee":null}}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				{
+				  "assignee": null,
+				  "key": "PROJ-1",
+				  "summary": "Login",
+				  "url": "https://jira-url/browse/PROJ-1"
+				}
+			`),
+		},
+		{
+			name: "should filter issue with jq",
+			args: []string{"--jq", `.fields.labels | join(",")`},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(issueJSON),
+				)
+			},
+			expectOut: "web,safari\n",
+		},
+		{
+			name: "should format issue with template",
+			args: []string{"--json=key,summary,status", "--template", `{{tablerow .key (truncate 10 .summary) .status.name}}{{tablerow "KEY-100" "Short" "Done"}}`},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(issueJSON),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				PROJ-1   Login f...  In Progress
+				KEY-100  Short       Done
+			`),
+		},
+		{
+			name: "should print compact cards of several issues in given order",
+			key:  "PROJ-2 proj-1 PROJ-2",
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(issueJSON),
+				)
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-2"),
+					httpmock.StringResponse(`{"key": "PROJ-2", "fields": {"summary": "Empty", "issuetype": {"name": "Task"}}}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				PROJ-2 Empty
+				Task · Unassigned
+				https://jira-url/browse/PROJ-2
+
+				PROJ-1 Login fails on Safari
+				In Progress · Bug · Jane Doe · High
+				https://jira-url/browse/PROJ-1
+			`),
+		},
+		{
+			name:  "should read keys from stdin and export json array",
+			key:   "-",
+			stdin: "PROJ-1\tLogin fails\n\nPROJ-2\tEmpty\n",
+			piped: true,
+			args:  []string{"--json=key,summary"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key":"PROJ-1","fields":{"summary":"Login"}}`),
+				)
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-2"),
+					httpmock.StringResponse(`{"key":"PROJ-2","fields":{"summary":"Empty"}}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				[
+				  {
+				    "key": "PROJ-1",
+				    "summary": "Login"
+				  },
+				  {
+				    "key": "PROJ-2",
+				    "summary": "Empty"
+				  }
+				]
+			`),
+		},
+		{
+			name: "should report missing issue and print others",
+			key:  "PROJ-9 PROJ-1",
+			args: []string{"--jq", ".[].key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-9"),
+					httpmock.StatusStringResponse(404, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`),
+				)
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StringResponse(`{"key":"PROJ-1","fields":{"summary":"Login"}}`),
+				)
+			},
+			expectOut: "PROJ-1\n",
+			expectErr: "cannot get 1 of 2 issues:\n  cannot get issue PROJ-9",
+		},
+		{
+			name: "should return api error",
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/issue/PROJ-1"),
+					httpmock.StatusStringResponse(404, `{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`),
+				)
+			},
+			expectErr: "cannot get issue PROJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			tt.stub(reg)
+
+			out := &bytes.Buffer{}
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CurrentBranchFunc = func() (string, error) {
+				return "feature/proj-1/login", nil
+			}
+			f := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				IOStream: &iostreams.IOStream{Out: out},
+			}
+			if tt.stdin != "" {
+				f.IOStream.In = strings.NewReader(tt.stdin)
+				f.IOStream.SetNeverPrompt(true)
+				f.IOStream.SetInPiped(tt.piped)
+			}
+
+			var args []string
+			switch tt.key {
+			case "":
+				args = append(args, "PROJ-1")
+			case "-":
+				// key is taken from branch
+			default:
+				args = append(args, strings.Fields(tt.key)...)
+			}
+			args = append(args, tt.args...)
+
+			cmd := NewGetCmd(f)
+			cmd.SetArgs(args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			// when
+			_, err := cmd.ExecuteC()
+
+			// then
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				assert.ErrorContains(t, err, "Issue does not exist")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectOut, out.String())
+		})
+	}
+}
+
+func TestPrintComments(t *testing.T) {
+	var comments []*issues.Comment
+	for i, text := range []string{"first", "second", "third"} {
+		comments = append(comments, &issues.Comment{
+			ID:      strconv.Itoa(i + 1),
+			Author:  &issues.User{DisplayName: "A"},
+			Created: "2022-11-01T10:00:00.000+0000",
+			Body:    adf.FromMarkdown(text, nil),
+		})
+	}
+
+	out := &bytes.Buffer{}
+	printComments(out, iostreams.NewColorScheme(false), comments, 3, 1)
+	assert.Equal(t, "\nLatest 1 of 3 comments\n\nA 2022-11-01 10:00\n│ third\n", out.String())
+
+	out.Reset()
+	printComments(out, iostreams.NewColorScheme(false), comments, 3, 0)
+	assert.Contains(t, out.String(), "\nComments\n")
+	assert.Contains(t, out.String(), "│ first\n")
+}
@@ -0,0 +1,344 @@
+package get
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+const (
+	// latestComments is a number of comments shown on issue card
+	latestComments = 3
+
+	sprintFieldType   = "com.pyxis.greenhopper.jira:gh-sprint"
+	epicLinkFieldType = "com.pyxis.greenhopper.jira:gh-epic-link"
+	epicIssueType     = "Epic"
+)
+
+// issueView is an issue read with expanded schema, so that
+// custom fields like sprint or epic link can be found by their type
+type issueView struct {
+	Key         string                 `json:"key"`
+	Fields      viewFields             `json:"fields"`
+	Schema      map[string]fieldSchema `json:"schema"`
+	Changelog   *viewChangelog         `json:"changelog"`
+	Transitions []*viewTransition      `json:"transitions"`
+	// custom holds values of all fields, custom fields are looked up by schema
+	custom map[string]json.RawMessage
+}
+
+type fieldSchema struct {
+	Custom string `json:"custom"`
+}
+
+type viewFields struct {
+	Summary   string     `json:"summary"`
+	IssueType namedValue `json:"issuetype"`
+	Status    *struct {
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"status"`
+	Assignee    *viewUser   `json:"assignee"`
+	Priority    *namedValue `json:"priority"`
+	Labels      []string    `json:"labels"`
+	Parent      *viewIssue  `json:"parent"`
+	Description *adf.Node   `json:"description"`
+	Comment     struct {
+		Comments []*issues.Comment `json:"comments"`
+		Total    int               `json:"total"`
+	} `json:"comment"`
+}
+
+type namedValue struct {
+	Name string `json:"name"`
+}
+
+type viewUser struct {
+	DisplayName string `json:"displayName"`
+}
+
+type viewIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary   string     `json:"summary"`
+		IssueType namedValue `json:"issuetype"`
+	} `json:"fields"`
+}
+
+type viewChangelog struct {
+	Histories []struct {
+		Author  *viewUser `json:"author"`
+		Created string    `json:"created"`
+		Items   []struct {
+			Field      string `json:"field"`
+			FromString string `json:"fromString"`
+			ToString   string `json:"toString"`
+		} `json:"items"`
+	} `json:"histories"`
+	Total int `json:"total"`
+}
+
+type viewTransition struct {
+	Name string `json:"name"`
+	To   struct {
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"to"`
+}
+
+type viewSprint struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+func (v *issueView) UnmarshalJSON(data []byte) error {
+	type plain issueView
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v.custom = raw.Fields
+	return nil
+}
+
+// customField decodes value of the first field of given custom type into v,
+// it reports whether such field is set
+func (v *issueView) customField(fieldType string, value interface{}) bool {
+	for key, schema := range v.Schema {
+		if schema.Custom != fieldType {
+			continue
+		}
+		raw, ok := v.custom[key]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		if err := json.Unmarshal(raw, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sprint returns active sprint of the issue, or the latest one, when
+// issue was moved through several sprints
+func (v *issueView) sprint() string {
+	var sprints []viewSprint
+	if !v.customField(sprintFieldType, &sprints) || len(sprints) == 0 {
+		return ""
+	}
+	for _, s := range sprints {
+		if s.State == "active" {
+			return s.Name
+		}
+	}
+	return sprints[len(sprints)-1].Name
+}
+
+// epic returns epic of the issue, it is either its parent in team-managed
+// projects, or issue referenced by epic link in company-managed ones
+func (v *issueView) epic() string {
+	if p := v.Fields.Parent; p != nil && p.Fields.IssueType.Name == epicIssueType {
+		return p.Key + " " + p.Fields.Summary
+	}
+	var key string
+	v.customField(epicLinkFieldType, &key)
+	return key
+}
+
+// sections are optional parts of issue card
+type sections struct {
+	// comments is a number of latest comments shown, all when it is not positive
+	comments    int
+	history     bool
+	transitions bool
+}
+
+// printIssue writes issue card: its main fields, description, comments
+// and requested sections
+func printIssue(out io.Writer, cs *iostreams.ColorScheme, v *issueView, url string, s sections) {
+	f := v.Fields
+	fmt.Fprintf(out, "%s %s\n", cs.Bold(v.Key), cs.Bold(f.Summary))
+
+	status := ""
+	if f.Status != nil {
+		status = issues.StatusColor(cs, f.Status.StatusCategory.Key)(f.Status.Name)
+	}
+	assignee := cs.Gray("Unassigned")
+	if f.Assignee != nil {
+		assignee = f.Assignee.DisplayName
+	}
+	priority := ""
+	if f.Priority != nil {
+		priority = f.Priority.Name
+	}
+	parent := ""
+	if f.Parent != nil && f.Parent.Fields.IssueType.Name != epicIssueType {
+		parent = f.Parent.Key + " " + f.Parent.Fields.Summary
+	}
+
+	rows := []struct{ name, value string }{
+		{"Status", status},
+		{"Type", f.IssueType.Name},
+		{"Assignee", assignee},
+		{"Priority", priority},
+		{"Labels", strings.Join(f.Labels, ", ")},
+		{"Sprint", v.sprint()},
+		{"Parent", parent},
+		{"Epic", v.epic()},
+	}
+	for _, r := range rows {
+		if r.value != "" {
+			fmt.Fprintf(out, "%s %s\n", cs.Gray(fmt.Sprintf("%-9s", r.name+":")), r.value)
+		}
+	}
+
+	description := adf.ToTerminal(f.Description, cs)
+	if description == "" {
+		description = cs.Gray("No description provided")
+	}
+	fmt.Fprintf(out, "\n%s\n", description)
+
+	printComments(out, cs, f.Comment.Comments, f.Comment.Total, s.comments)
+	if s.history {
+		printHistory(out, cs, v.Changelog)
+	}
+	if s.transitions {
+		status := ""
+		if f.Status != nil {
+			status = f.Status.Name
+		}
+		printTransitions(out, cs, status, v.Transitions)
+	}
+
+	fmt.Fprintf(out, "\n%s\n", cs.Gray("View this issue on Jira: "+url))
+}
+
+// printCompactIssue writes issue summary, its status, type, assignee and priority in one line
+func printCompactIssue(out io.Writer, cs *iostreams.ColorScheme, v *issueView, url string) {
+	f := v.Fields
+	fmt.Fprintf(out, "%s %s\n", cs.Bold(v.Key), cs.Bold(f.Summary))
+
+	var details []string
+	if f.Status != nil {
+		details = append(details, issues.StatusColor(cs, f.Status.StatusCategory.Key)(f.Status.Name))
+	}
+	if f.IssueType.Name != "" {
+		details = append(details, f.IssueType.Name)
+	}
+	if f.Assignee != nil {
+		details = append(details, f.Assignee.DisplayName)
+	} else {
+		details = append(details, cs.Gray("Unassigned"))
+	}
+	if f.Priority != nil {
+		details = append(details, f.Priority.Name)
+	}
+	fmt.Fprintln(out, strings.Join(details, cs.Gray(" · ")))
+	fmt.Fprintln(out, cs.Gray(url))
+}
+
+// printComments writes latest comments, replies are shown under comments they answer
+func printComments(out io.Writer, cs *iostreams.ColorScheme, all []*issues.Comment, total, limit int) {
+	comments := all
+	if limit > 0 && len(comments) > limit {
+		comments = comments[len(comments)-limit:]
+	}
+	if total < len(all) {
+		total = len(all)
+	}
+	if len(comments) == 0 {
+		return
+	}
+
+	title := "Comments"
+	if total > len(comments) {
+		title = fmt.Sprintf("Latest %d of %d comments", len(comments), total)
+	}
+	fmt.Fprintf(out, "\n%s\n", cs.Bold(title))
+
+	issues.PrintThreads(out, cs, comments, false)
+}
+
+// printHistory writes changes of issue fields, the latest first
+func printHistory(out io.Writer, cs *iostreams.ColorScheme, changelog *viewChangelog) {
+	fmt.Fprintf(out, "\n%s\n", cs.Bold("History"))
+	if changelog == nil || len(changelog.Histories) == 0 {
+		fmt.Fprintf(out, "%s\n", cs.Gray("No changes"))
+		return
+	}
+
+	histories := changelog.Histories
+	sort.SliceStable(histories, func(i, j int) bool {
+		return parseTime(histories[i].Created).After(parseTime(histories[j].Created))
+	})
+
+	for _, h := range histories {
+		author := "Unknown"
+		if h.Author != nil {
+			author = h.Author.DisplayName
+		}
+		fmt.Fprintf(out, "\n%s %s\n", cs.Gray(issues.FormatTime(h.Created)), cs.Bold(author))
+		for _, item := range h.Items {
+			fmt.Fprintf(out, "  %s %s → %s\n", item.Field+":", changeValue(cs, item.FromString), changeValue(cs, item.ToString))
+		}
+	}
+	if changelog.Total > len(histories) {
+		fmt.Fprintf(out, "\n%s\n", cs.Gray(fmt.Sprintf("%d of %d changes are shown", len(histories), changelog.Total)))
+	}
+}
+
+func changeValue(cs *iostreams.ColorScheme, value string) string {
+	if value == "" {
+		return cs.Gray("none")
+	}
+	// long values, e.g. descriptions, are shortened to one line
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > 60 {
+		value = string(runes[:57]) + "..."
+	}
+	return value
+}
+
+// printTransitions writes transitions available from current status
+func printTransitions(out io.Writer, cs *iostreams.ColorScheme, status string, transitions []*viewTransition) {
+	title := "Transitions"
+	if status != "" {
+		title = "Transitions from " + status
+	}
+	fmt.Fprintf(out, "\n%s\n", cs.Bold(title))
+	if len(transitions) == 0 {
+		fmt.Fprintf(out, "%s\n", cs.Gray("No transitions available"))
+		return
+	}
+
+	for _, t := range transitions {
+		to := issues.StatusColor(cs, t.To.StatusCategory.Key)(t.To.Name)
+		if t.Name == t.To.Name {
+			fmt.Fprintf(out, "  %s\n", to)
+			continue
+		}
+		fmt.Fprintf(out, "  %s → %s\n", t.Name, to)
+	}
+}
+
+func parseTime(value string) time.Time {
+	t, _ := time.Parse(issues.JiraTimeLayout, value)
+	return t
+}
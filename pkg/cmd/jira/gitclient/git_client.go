@@ -1,17 +1,34 @@
 package gitclient
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stirboy/jh/pkg/iostreams"
 )
 
 //go:generate moq -rm -out git_client_mock.go . GitClient
 type GitClient interface {
 	CreateBranchWithCheckout(string) error
+	RootDir() (string, error)
+	CurrentBranch() (string, error)
+	Commit(string) (*Commit, error)
+	Log(string, string) ([]Commit, error)
+	Diff(string, string) ([]string, error)
+}
+
+// Commit describes git commit, Files are paths changed by the commit
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+	Files   []string
 }
 
 // client implements GitClient
@@ -55,3 +72,279 @@ func (c *Client) CreateBranchWithCheckout(branchName string) error {
 
 	return nil
 }
+
+// RootDir returns top level directory of git repository
+// which contains current directory
+func (c *Client) RootDir() (string, error) {
+	r, err := c.open()
+	if err != nil {
+		return "", err
+	}
+
+	worktree, err := r.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	return worktree.Filesystem.Root(), nil
+}
+
+// CurrentBranch returns short name of checked out branch
+func (c *Client) CurrentBranch() (string, error) {
+	r, err := c.open()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", errors.New("HEAD is detached, no branch is checked out")
+	}
+
+	return head.Name().Short(), nil
+}
+
+// Commit returns commit of given revision, e.g. HEAD~1 or a hash,
+// together with files it changed compared to its first parent
+func (c *Client) Commit(rev string) (*Commit, error) {
+	r, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := resolveCommit(r, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := changedFiles(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newCommit(commit)
+	result.Files = files
+	return &result, nil
+}
+
+// Log returns commits of branch which are not in base branch, newest first,
+// like git log base..branch. Empty base means default branch of the repository.
+func (c *Client) Log(branch, base string) ([]Commit, error) {
+	r, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+
+	tip, baseCommit, err := resolveBranches(r, branch, base)
+	if err != nil {
+		return nil, err
+	}
+
+	// base may have been merged into branch, so walk stops at every commit
+	// reachable from base, not only at the merge base
+	hidden, err := reachableFromBase(tip, baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	err = object.NewCommitPreorderIter(tip, hidden, nil).ForEach(func(commit *object.Commit) error {
+		commits = append(commits, newCommit(commit))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// reachableFromBase walks histories of tip and base together, newest commits first,
+// and marks commits reachable from base. Walk ends once every pending commit is
+// reachable from base, like git rev-list does, so base history below the point
+// where both histories meet is not read.
+func reachableFromBase(tip, base *object.Commit) (map[plumbing.Hash]bool, error) {
+	hidden := map[plumbing.Hash]bool{base.Hash: true}
+	seen := map[plumbing.Hash]bool{tip.Hash: true, base.Hash: true}
+	queue := []*object.Commit{tip, base}
+	if tip.Hash == base.Hash {
+		queue = queue[:1]
+	}
+
+	for !allHidden(queue, hidden) {
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].Committer.When.After(queue[j].Committer.When)
+		})
+		commit := queue[0]
+		queue = queue[1:]
+
+		err := commit.Parents().ForEach(func(parent *object.Commit) error {
+			// parent is walked again when it turns out to be reachable from base
+			// after it was reached from tip
+			if seen[parent.Hash] && (hidden[parent.Hash] || !hidden[commit.Hash]) {
+				return nil
+			}
+			seen[parent.Hash] = true
+			if hidden[commit.Hash] {
+				hidden[parent.Hash] = true
+			}
+			queue = append(queue, parent)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return hidden, nil
+}
+
+func allHidden(commits []*object.Commit, hidden map[plumbing.Hash]bool) bool {
+	for _, commit := range commits {
+		if !hidden[commit.Hash] {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns files changed on branch since it was forked from base branch,
+// like git diff base...branch. Empty base means default branch of the repository.
+func (c *Client) Diff(branch, base string) ([]string, error) {
+	r, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+
+	tip, mergeBase, err := branchPoint(r, branch, base)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := mergeBase.Tree()
+	if err != nil {
+		return nil, err
+	}
+	to, err := tip.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	return changedFiles(from, to)
+}
+
+func (c *Client) open() (*git.Repository, error) {
+	return git.PlainOpenWithOptions(c.GitPath, &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+}
+
+func resolveCommit(r *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := r.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", rev, err)
+	}
+
+	return r.CommitObject(*hash)
+}
+
+// branchPoint returns tip of branch and the commit it was forked from
+func branchPoint(r *git.Repository, branch, base string) (*object.Commit, *object.Commit, error) {
+	tip, baseCommit, err := resolveBranches(r, branch, base)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bases, err := tip.MergeBase(baseCommit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bases) == 0 {
+		return nil, nil, fmt.Errorf("%s and %s have no common history", branch, base)
+	}
+
+	return tip, bases[0], nil
+}
+
+// resolveBranches returns tips of branch and base branch, empty base means default branch
+func resolveBranches(r *git.Repository, branch, base string) (*object.Commit, *object.Commit, error) {
+	if base == "" {
+		var err error
+		if base, err = defaultBranch(r); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tip, err := resolveCommit(r, branch)
+	if err != nil {
+		return nil, nil, err
+	}
+	baseCommit, err := resolveCommit(r, base)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tip, baseCommit, nil
+}
+
+// defaultBranch returns branch origin/HEAD points to, or main or master
+func defaultBranch(r *git.Repository) (string, error) {
+	if ref, err := r.Reference(plumbing.NewRemoteHEADReferenceName("origin"), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		return ref.Target().Short(), nil
+	}
+
+	for _, name := range []string{"main", "master"} {
+		if _, err := r.Reference(plumbing.NewBranchReferenceName(name), false); err == nil {
+			return name, nil
+		}
+	}
+
+	return "", errors.New("cannot find default branch, neither origin/HEAD, main nor master exists")
+}
+
+func changedFiles(from, to *object.Tree) ([]string, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			// deleted file
+			name = change.From.Name
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func newCommit(commit *object.Commit) Commit {
+	subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return Commit{
+		Hash:    commit.Hash.String(),
+		Subject: strings.TrimSpace(subject),
+		Body:    strings.TrimSpace(body),
+	}
+}
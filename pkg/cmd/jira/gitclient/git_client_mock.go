@@ -17,9 +17,24 @@ var _ GitClient = &GitClientMock{}
 //
 //		// make and configure a mocked GitClient
 //		mockedGitClient := &GitClientMock{
+//			CommitFunc: func(s string) (*Commit, error) {
+//				panic("mock out the Commit method")
+//			},
 //			CreateBranchWithCheckoutFunc: func(s string) error {
 //				panic("mock out the CreateBranchWithCheckout method")
 //			},
+//			CurrentBranchFunc: func() (string, error) {
+//				panic("mock out the CurrentBranch method")
+//			},
+//			DiffFunc: func(s1 string, s2 string) ([]string, error) {
+//				panic("mock out the Diff method")
+//			},
+//			LogFunc: func(s1 string, s2 string) ([]Commit, error) {
+//				panic("mock out the Log method")
+//			},
+//			RootDirFunc: func() (string, error) {
+//				panic("mock out the RootDir method")
+//			},
 //		}
 //
 //		// use mockedGitClient in code that requires GitClient
@@ -27,18 +42,95 @@ var _ GitClient = &GitClientMock{}
 //
 //	}
 type GitClientMock struct {
+	// CommitFunc mocks the Commit method.
+	CommitFunc func(s string) (*Commit, error)
+
 	// CreateBranchWithCheckoutFunc mocks the CreateBranchWithCheckout method.
 	CreateBranchWithCheckoutFunc func(s string) error
 
+	// CurrentBranchFunc mocks the CurrentBranch method.
+	CurrentBranchFunc func() (string, error)
+
+	// DiffFunc mocks the Diff method.
+	DiffFunc func(s1 string, s2 string) ([]string, error)
+
+	// LogFunc mocks the Log method.
+	LogFunc func(s1 string, s2 string) ([]Commit, error)
+
+	// RootDirFunc mocks the RootDir method.
+	RootDirFunc func() (string, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// Commit holds details about calls to the Commit method.
+		Commit []struct {
+			// S is the s argument value.
+			S string
+		}
 		// CreateBranchWithCheckout holds details about calls to the CreateBranchWithCheckout method.
 		CreateBranchWithCheckout []struct {
 			// S is the s argument value.
 			S string
 		}
+		// CurrentBranch holds details about calls to the CurrentBranch method.
+		CurrentBranch []struct {
+		}
+		// Diff holds details about calls to the Diff method.
+		Diff []struct {
+			// S1 is the s1 argument value.
+			S1 string
+			// S2 is the s2 argument value.
+			S2 string
+		}
+		// Log holds details about calls to the Log method.
+		Log []struct {
+			// S1 is the s1 argument value.
+			S1 string
+			// S2 is the s2 argument value.
+			S2 string
+		}
+		// RootDir holds details about calls to the RootDir method.
+		RootDir []struct {
+		}
 	}
+	lockCommit                   sync.RWMutex
 	lockCreateBranchWithCheckout sync.RWMutex
+	lockCurrentBranch            sync.RWMutex
+	lockDiff                     sync.RWMutex
+	lockLog                      sync.RWMutex
+	lockRootDir                  sync.RWMutex
+}
+
+// Commit calls CommitFunc.
+func (mock *GitClientMock) Commit(s string) (*Commit, error) {
+	if mock.CommitFunc == nil {
+		panic("GitClientMock.CommitFunc: method is nil but GitClient.Commit was just called")
+	}
+	callInfo := struct {
+		S string
+	}{
+		S: s,
+	}
+	mock.lockCommit.Lock()
+	mock.calls.Commit = append(mock.calls.Commit, callInfo)
+	mock.lockCommit.Unlock()
+	return mock.CommitFunc(s)
+}
+
+// CommitCalls gets all the calls that were made to Commit.
+// Check the length with:
+//
+//	len(mockedGitClient.CommitCalls())
+func (mock *GitClientMock) CommitCalls() []struct {
+	S string
+} {
+	var calls []struct {
+		S string
+	}
+	mock.lockCommit.RLock()
+	calls = mock.calls.Commit
+	mock.lockCommit.RUnlock()
+	return calls
 }
 
 // CreateBranchWithCheckout calls CreateBranchWithCheckoutFunc.
@@ -72,3 +164,129 @@ func (mock *GitClientMock) CreateBranchWithCheckoutCalls() []struct {
 	mock.lockCreateBranchWithCheckout.RUnlock()
 	return calls
 }
+
+// CurrentBranch calls CurrentBranchFunc.
+func (mock *GitClientMock) CurrentBranch() (string, error) {
+	if mock.CurrentBranchFunc == nil {
+		panic("GitClientMock.CurrentBranchFunc: method is nil but GitClient.CurrentBranch was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockCurrentBranch.Lock()
+	mock.calls.CurrentBranch = append(mock.calls.CurrentBranch, callInfo)
+	mock.lockCurrentBranch.Unlock()
+	return mock.CurrentBranchFunc()
+}
+
+// CurrentBranchCalls gets all the calls that were made to CurrentBranch.
+// Check the length with:
+//
+//	len(mockedGitClient.CurrentBranchCalls())
+func (mock *GitClientMock) CurrentBranchCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCurrentBranch.RLock()
+	calls = mock.calls.CurrentBranch
+	mock.lockCurrentBranch.RUnlock()
+	return calls
+}
+
+// Diff calls DiffFunc.
+func (mock *GitClientMock) Diff(s1 string, s2 string) ([]string, error) {
+	if mock.DiffFunc == nil {
+		panic("GitClientMock.DiffFunc: method is nil but GitClient.Diff was just called")
+	}
+	callInfo := struct {
+		S1 string
+		S2 string
+	}{
+		S1: s1,
+		S2: s2,
+	}
+	mock.lockDiff.Lock()
+	mock.calls.Diff = append(mock.calls.Diff, callInfo)
+	mock.lockDiff.Unlock()
+	return mock.DiffFunc(s1, s2)
+}
+
+// DiffCalls gets all the calls that were made to Diff.
+// Check the length with:
+//
+//	len(mockedGitClient.DiffCalls())
+func (mock *GitClientMock) DiffCalls() []struct {
+	S1 string
+	S2 string
+} {
+	var calls []struct {
+		S1 string
+		S2 string
+	}
+	mock.lockDiff.RLock()
+	calls = mock.calls.Diff
+	mock.lockDiff.RUnlock()
+	return calls
+}
+
+// Log calls LogFunc.
+func (mock *GitClientMock) Log(s1 string, s2 string) ([]Commit, error) {
+	if mock.LogFunc == nil {
+		panic("GitClientMock.LogFunc: method is nil but GitClient.Log was just called")
+	}
+	callInfo := struct {
+		S1 string
+		S2 string
+	}{
+		S1: s1,
+		S2: s2,
+	}
+	mock.lockLog.Lock()
+	mock.calls.Log = append(mock.calls.Log, callInfo)
+	mock.lockLog.Unlock()
+	return mock.LogFunc(s1, s2)
+}
+
+// LogCalls gets all the calls that were made to Log.
+// Check the length with:
+//
+//	len(mockedGitClient.LogCalls())
+func (mock *GitClientMock) LogCalls() []struct {
+	S1 string
+	S2 string
+} {
+	var calls []struct {
+		S1 string
+		S2 string
+	}
+	mock.lockLog.RLock()
+	calls = mock.calls.Log
+	mock.lockLog.RUnlock()
+	return calls
+}
+
+// RootDir calls RootDirFunc.
+func (mock *GitClientMock) RootDir() (string, error) {
+	if mock.RootDirFunc == nil {
+		panic("GitClientMock.RootDirFunc: method is nil but GitClient.RootDir was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRootDir.Lock()
+	mock.calls.RootDir = append(mock.calls.RootDir, callInfo)
+	mock.lockRootDir.Unlock()
+	return mock.RootDirFunc()
+}
+
+// RootDirCalls gets all the calls that were made to RootDir.
+// Check the length with:
+//
+//	len(mockedGitClient.RootDirCalls())
+func (mock *GitClientMock) RootDirCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRootDir.RLock()
+	calls = mock.calls.RootDir
+	mock.lockRootDir.RUnlock()
+	return calls
+}
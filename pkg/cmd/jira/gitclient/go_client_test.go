@@ -2,7 +2,14 @@ package gitclient
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 
 	"github.com/stirboy/jh/pkg/iostreams"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +32,162 @@ func TestCreateBranchWithCheckout(t *testing.T) {
 	assert.Equal(t, "switched to branch: 'feature/test'\n", out.String())
 }
 
+func TestRootDir(t *testing.T) {
+	// given
+	repo := StubLocalGitRepository(t)
+	subDir := filepath.Join(repo, "pkg", "sub")
+	assert.NoError(t, os.MkdirAll(subDir, 0755))
+	c := NewClient(subDir, &iostreams.IOStream{})
+
+	// when
+	root, err := c.RootDir()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, repo, root)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	// given
+	repo := StubLocalGitRepository(t)
+	c := NewClient(repo, &iostreams.IOStream{Out: &bytes.Buffer{}})
+	assert.NoError(t, c.CreateBranchWithCheckout("feature/login"))
+
+	// when
+	branch, err := c.CurrentBranch()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "feature/login", branch)
+}
+
+func TestCommit(t *testing.T) {
+	// given
+	repo := StubLocalGitRepository(t)
+	commitFiles(t, repo, "Fix login\n\nSession cookie was not refreshed.\n", "README.md", "auth/login.go")
+	commitFiles(t, repo, "Update docs", "README.md")
+	c := NewClient(repo, &iostreams.IOStream{})
+
+	// when
+	commit, err := c.Commit("HEAD~1")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Fix login", commit.Subject)
+	assert.Equal(t, "Session cookie was not refreshed.", commit.Body)
+	assert.Equal(t, []string{"README.md", "auth/login.go"}, commit.Files)
+}
+
+func TestLogAndDiff(t *testing.T) {
+	// given
+	repo := StubLocalGitRepository(t)
+	c := NewClient(repo, &iostreams.IOStream{Out: &bytes.Buffer{}})
+	assert.NoError(t, c.CreateBranchWithCheckout("feature/login"))
+	commitFiles(t, repo, "Add login form", "web/login.html")
+	commitFiles(t, repo, "Validate password", "web/login.html", "auth/password.go")
+
+	// when
+	commits, err := c.Log("feature/login", "")
+	assert.NoError(t, err)
+	files, err := c.Diff("feature/login", "master")
+	assert.NoError(t, err)
+
+	// then
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "Validate password", commits[0].Subject)
+	assert.Equal(t, "Add login form", commits[1].Subject)
+	assert.Equal(t, []string{"auth/password.go", "web/login.html"}, files)
+}
+
+func TestLogSkipsMergedBaseBranch(t *testing.T) {
+	// given
+	repo := StubLocalGitRepository(t)
+	c := NewClient(repo, &iostreams.IOStream{Out: &bytes.Buffer{}})
+	assert.NoError(t, c.CreateBranchWithCheckout("feature/login"))
+	commitFiles(t, repo, "Add login form", "web/login.html")
+	checkout(t, repo, "master")
+	commitFiles(t, repo, "Bump dependencies", "go.mod")
+	commitFiles(t, repo, "Update changelog", "CHANGELOG.md")
+	checkout(t, repo, "feature/login")
+	mergeBranch(t, repo, "master", "go.mod", "CHANGELOG.md")
+	commitFiles(t, repo, "Validate password", "auth/password.go")
+
+	// when
+	commits, err := c.Log("feature/login", "master")
+
+	// then
+	assert.NoError(t, err)
+	var subjects []string
+	for _, commit := range commits {
+		subjects = append(subjects, commit.Subject)
+	}
+	assert.Equal(t, []string{"Validate password", "Merge branch 'master'", "Add login form"}, subjects)
+}
+
+// checkout switches worktree of repo to existing branch
+func checkout(t *testing.T, repo, branch string) {
+	t.Helper()
+	r, err := git.PlainOpen(repo)
+	assert.NoError(t, err)
+	worktree, err := r.Worktree()
+	assert.NoError(t, err)
+	assert.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch)}))
+}
+
+// mergeBranch commits merge of branch into HEAD, files are copied from branch,
+// merges never conflict in tests
+func mergeBranch(t *testing.T, repo, branch string, files ...string) {
+	t.Helper()
+	r, err := git.PlainOpen(repo)
+	assert.NoError(t, err)
+	worktree, err := r.Worktree()
+	assert.NoError(t, err)
+	head, err := r.Head()
+	assert.NoError(t, err)
+	other, err := r.Reference(plumbing.NewBranchReferenceName(branch), false)
+	assert.NoError(t, err)
+	commit, err := r.CommitObject(other.Hash())
+	assert.NoError(t, err)
+
+	for _, f := range files {
+		file, err := commit.File(f)
+		assert.NoError(t, err)
+		content, err := file.Contents()
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(repo, f), []byte(content), 0644))
+		_, err = worktree.Add(f)
+		assert.NoError(t, err)
+	}
+
+	_, err = worktree.Commit("Merge branch '"+branch+"'", &git.CommitOptions{
+		Author:  &object.Signature{Name: "jh", Email: "jh@example.com", When: time.Now()},
+		Parents: []plumbing.Hash{head.Hash(), other.Hash()},
+	})
+	assert.NoError(t, err)
+}
+
+// commitFiles writes message to files and commits them
+func commitFiles(t *testing.T, repo, message string, files ...string) {
+	t.Helper()
+	r, err := git.PlainOpen(repo)
+	assert.NoError(t, err)
+	worktree, err := r.Worktree()
+	assert.NoError(t, err)
+
+	for _, f := range files {
+		path := filepath.Join(repo, f)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(message), 0644))
+		_, err = worktree.Add(f)
+		assert.NoError(t, err)
+	}
+
+	_, err = worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "jh", Email: "jh@example.com", When: time.Now()},
+	})
+	assert.NoError(t, err)
+}
+
 func AssertEquals[T comparable](t *testing.T, a, b T) {
 
 }
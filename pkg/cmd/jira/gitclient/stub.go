@@ -1,9 +1,13 @@
 package gitclient
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func NewGitClientMock() *GitClientMock {
@@ -11,6 +15,12 @@ func NewGitClientMock() *GitClientMock {
 		CreateBranchWithCheckoutFunc: func(s string) error {
 			return nil
 		},
+		RootDirFunc: func() (string, error) {
+			return "", git.ErrRepositoryNotExists
+		},
+		CurrentBranchFunc: func() (string, error) {
+			return "", git.ErrRepositoryNotExists
+		},
 	}
 }
 
@@ -27,3 +37,35 @@ func StubGitRepository(t *testing.T) string {
 
 	return tempDir
 }
+
+// StubLocalGitRepository creates repository with a single commit on master,
+// it doesn't need network unlike StubGitRepository
+func StubLocalGitRepository(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	r, err := git.PlainInit(tempDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// branches can be created only when HEAD points to a commit
+	if err = os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = worktree.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = worktree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "jh", Email: "jh@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tempDir
+}
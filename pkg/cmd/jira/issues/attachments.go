@@ -0,0 +1,111 @@
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// ProgressFunc is called as file is being uploaded with number of bytes sent so far
+type ProgressFunc func(sent, total int64)
+
+// UploadAttachment attaches file to the issue. File is streamed, so that
+// progress reflects bytes actually sent to jira and large files are not kept in memory.
+func UploadAttachment(jiraClient *jira.Client, issueKey, path string, progress ProgressFunc) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// multipart body is file part header, file content and closing boundary
+	head := &bytes.Buffer{}
+	writer := multipart.NewWriter(head)
+	if _, err = writer.CreateFormFile("file", filepath.Base(path)); err != nil {
+		return err
+	}
+	headLen := head.Len()
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	tail := append([]byte(nil), head.Bytes()[headLen:]...)
+	head.Truncate(headLen)
+
+	req, err := jiraClient.NewMultiPartRequest(context.Background(), http.MethodPost,
+		"rest/api/3/issue/"+url.PathEscape(issueKey)+"/attachments", &bytes.Buffer{})
+	if err != nil {
+		return err
+	}
+
+	content := &progressReader{r: f, total: info.Size(), progress: progress}
+	req.Body = io.NopCloser(io.MultiReader(head, content, bytes.NewReader(tail)))
+	req.GetBody = nil
+	req.ContentLength = int64(headLen) + info.Size() + int64(len(tail))
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		if resp == nil {
+			return err
+		}
+		return responseError(resp)
+	}
+
+	return nil
+}
+
+// responseError describes failed request by jira error messages, uploads are
+// often rejected by proxies with html pages, so status is used as a fallback
+func responseError(resp *jira.Response) error {
+	defer resp.Body.Close()
+
+	var result struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(body, &result) == nil {
+		messages := append([]string{}, result.ErrorMessages...)
+		for _, m := range result.Errors {
+			messages = append(messages, m)
+		}
+		if len(messages) > 0 {
+			sort.Strings(messages[len(result.ErrorMessages):])
+			return errors.New(strings.Join(messages, "; "))
+		}
+	}
+
+	return errors.New(resp.Status)
+}
+
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if p.progress != nil && n > 0 {
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,115 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+// Comment is an issue comment with body in atlassian document format
+type Comment struct {
+	ID      string    `json:"id"`
+	Author  *User     `json:"author"`
+	Body    *adf.Node `json:"body"`
+	Created string    `json:"created"`
+	Updated string    `json:"updated"`
+	// ParentID is set on replies to other comments
+	ParentID interface{} `json:"parentId"`
+}
+
+type User struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+}
+
+// Parent returns id of comment this comment replies to, empty when it is not a reply
+func (c *Comment) Parent() string {
+	switch id := c.ParentID.(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
+
+// CommentPath returns api path of issue comments, or of a single comment when id is given
+func CommentPath(key, id string) string {
+	path := "rest/api/3/issue/" + url.PathEscape(key) + "/comment"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+// AddComment adds comment with body in atlassian document format to the issue
+func AddComment(jiraClient *jira.Client, key string, body *adf.Node) (*Comment, error) {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost,
+		CommentPath(key, ""), map[string]interface{}{"body": body})
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &Comment{}
+	resp, err := jiraClient.Do(req, comment)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+	return comment, nil
+}
+
+// CommentURL returns link to comment in jira, issue is scrolled to the comment
+func CommentURL(baseURL, key, id string) string {
+	return fmt.Sprintf("%sbrowse/%s?focusedCommentId=%s", baseURL, key, url.QueryEscape(id))
+}
+
+// PrintThreads writes comments in given order, replies are shown under comments
+// they answer. With ids, id of every comment is shown next to its author.
+func PrintThreads(out io.Writer, cs *iostreams.ColorScheme, comments []*Comment, withIDs bool) {
+	shown := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		shown[c.ID] = true
+	}
+	replies := make(map[string][]*Comment)
+	var threads []*Comment
+	for _, c := range comments {
+		if parent := c.Parent(); parent != "" && shown[parent] {
+			replies[parent] = append(replies[parent], c)
+			continue
+		}
+		threads = append(threads, c)
+	}
+
+	var printThread func(c *Comment, indent string)
+	printThread = func(c *Comment, indent string) {
+		author := "Unknown"
+		if c.Author != nil {
+			author = c.Author.DisplayName
+		}
+		header := cs.Bold(author) + " " + cs.Gray(FormatTime(c.Created))
+		if c.Updated != "" && c.Updated != c.Created {
+			header += cs.Gray(" (edited)")
+		}
+		if withIDs {
+			header += " " + cs.Gray("#"+c.ID)
+		}
+		fmt.Fprintf(out, "\n%s%s\n", indent, header)
+		for _, line := range strings.Split(adf.ToTerminal(c.Body, cs), "\n") {
+			fmt.Fprintf(out, "%s%s%s\n", indent, cs.Gray("│ "), line)
+		}
+		for _, r := range replies[c.ID] {
+			printThread(r, indent+"    ")
+		}
+	}
+	for _, c := range threads {
+		printThread(c, "")
+	}
+}
@@ -0,0 +1,55 @@
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stirboy/jh/pkg/utils"
+)
+
+// issueAttributes are exported along with jira fields, they are not requested as fields
+var issueAttributes = []string{"key", "id", "self", "url", "changelog", "transitions"}
+
+// JiraFields returns fields to request from jira among exported fields
+func JiraFields(fields []string) []string {
+	var result []string
+	for _, f := range fields {
+		if !utils.Contains(issueAttributes, f) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// ExportedIssue flattens issue, so that its fields can be selected
+// next to its key, e.g. --json=key,summary
+func ExportedIssue(raw json.RawMessage, baseURL string) (map[string]interface{}, error) {
+	var issue struct {
+		Key         string                 `json:"key"`
+		ID          string                 `json:"id"`
+		Self        string                 `json:"self"`
+		Fields      map[string]interface{} `json:"fields"`
+		Changelog   interface{}            `json:"changelog"`
+		Transitions interface{}            `json:"transitions"`
+	}
+	if err := json.Unmarshal(raw, &issue); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"key":  issue.Key,
+		"id":   issue.ID,
+		"self": issue.Self,
+		"url":  fmt.Sprintf("%sbrowse/%s", baseURL, issue.Key),
+	}
+	if issue.Changelog != nil {
+		data["changelog"] = issue.Changelog
+	}
+	if issue.Transitions != nil {
+		data["transitions"] = issue.Transitions
+	}
+	for k, v := range issue.Fields {
+		data[k] = v
+	}
+	return data, nil
+}
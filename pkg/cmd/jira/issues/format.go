@@ -0,0 +1,30 @@
+package issues
+
+import (
+	"time"
+
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+// JiraTimeLayout is a layout of dates returned by jira api, e.g. updated or created
+const JiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// StatusColor returns style of status by its category
+func StatusColor(cs *iostreams.ColorScheme, category string) func(string) string {
+	switch category {
+	case "indeterminate":
+		return cs.Yellow
+	case "done":
+		return cs.Green
+	}
+	return cs.Blue
+}
+
+// FormatTime returns jira time in local time zone, value is returned as it is when it cannot be parsed
+func FormatTime(value string) string {
+	t, err := time.Parse(JiraTimeLayout, value)
+	if err != nil {
+		return value
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,32 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// GetRaw returns issue as it is returned by jira api, query selects fields
+// and expand options of the issue
+func GetRaw(jiraClient *jira.Client, key string, query url.Values) (json.RawMessage, error) {
+	path := "rest/api/3/issue/" + url.PathEscape(key)
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	resp, err := jiraClient.Do(req, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get issue %s: %w", key, jira.NewJiraError(resp, err))
+	}
+	return raw, nil
+}
@@ -0,0 +1,183 @@
+package issues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+const (
+	// DefaultKeyPattern matches issue keys in branches made by jh create -b: a path segment
+	// which is the key, e.g. feature/proj-123/login, or starts with the key followed by summary
+	// after type, e.g. bug/proj-123-login-fails. Versions like lodash-4.17.21 or release-2024.1
+	// are not keys.
+	DefaultKeyPattern = `(?i)(?:^|/)([a-z][a-z0-9_]*-[1-9][0-9]*)(?:$|/)|/([a-z][a-z0-9_]*-[1-9][0-9]*)-`
+
+	// recentIssuesLimit is a max number of issues offered by picker
+	recentIssuesLimit = 20
+	recentIssuesJQL   = "assignee = currentUser() OR reporter = currentUser() ORDER BY updated DESC"
+)
+
+// KeyResolver finds key of issue command works with, when it is not given as argument
+type KeyResolver struct {
+	Config     func() (config.Config, error)
+	JiraClient func() (*jira.Client, error)
+	GitClient  func() (gitclient.GitClient, error)
+	Prompter   prompt.Prompter
+	IOStream   *iostreams.IOStream
+}
+
+func NewKeyResolver(f *factory.Factory) *KeyResolver {
+	return &KeyResolver{
+		Config:     f.Config,
+		JiraClient: f.JiraClient,
+		GitClient:  f.GitClient,
+		Prompter:   f.Prompter,
+		IOStream:   f.IOStream,
+	}
+}
+
+// Resolve returns key given as argument, or key found in current git branch
+// by configuration.git.keyPattern. When there is none, or issue of branch key
+// is not found, user picks one of
+// recent issues.
+func (r *KeyResolver) Resolve(args []string) (string, error) {
+	if len(args) > 0 && args[0] != "" {
+		return strings.ToUpper(args[0]), nil
+	}
+
+	cfg, err := r.Config()
+	if err != nil {
+		return "", err
+	}
+	pattern, _ := cfg.GetNested([]string{"configuration", "git", "keyPattern"})
+
+	branch := ""
+	if gitClient, err := r.GitClient(); err == nil {
+		// not a git repository or detached HEAD, issue is picked instead
+		branch, _ = gitClient.CurrentBranch()
+	}
+	key := ""
+	if branch != "" {
+		if key, err = KeyFromBranch(branch, pattern); err != nil {
+			return "", err
+		}
+	}
+
+	jiraClient, err := r.JiraClient()
+	if err != nil {
+		return "", err
+	}
+
+	if key != "" {
+		// branch names like renovate/node-18-x look like keys, too
+		found, err := issueExists(jiraClient, key)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return key, nil
+		}
+		if !r.IOStream.CanPrompt() {
+			return "", fmt.Errorf("issue %s of branch %q not found, give key as argument or set configuration.git.keyPattern", key, branch)
+		}
+		return SelectRecentIssue(jiraClient, r.Prompter)
+	}
+
+	if !r.IOStream.CanPrompt() {
+		if branch == "" {
+			return "", errors.New("issue key is required, give it as argument or run command on branch with issue key")
+		}
+		return "", fmt.Errorf("cannot find issue key in branch %q, give it as argument or set configuration.git.keyPattern", branch)
+	}
+
+	return SelectRecentIssue(jiraClient, r.Prompter)
+}
+
+// issueExists reports whether issue can be found, e.g. key taken from branch may not be a key at all
+func issueExists(jiraClient *jira.Client, key string) (bool, error) {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet,
+		"rest/api/3/issue/"+url.PathEscape(key)+"?fields=summary", nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot get issue %s: %w", key, jira.NewJiraError(resp, err))
+	}
+	return true, nil
+}
+
+// keyPattern matches a whole issue key, e.g. PROJ-123
+var keyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-[1-9][0-9]*$`)
+
+// IsKey reports whether value looks like an issue key, e.g. PROJ-123 or proj-123
+func IsKey(value string) bool {
+	return keyPattern.MatchString(value)
+}
+
+// KeyFromBranch returns uppercased issue key found in branch name, empty when
+// there is none. Pattern defaults to DefaultKeyPattern, its first matched group
+// is the key, or the whole match when it has no groups.
+func KeyFromBranch(branch, pattern string) (string, error) {
+	if pattern == "" {
+		pattern = DefaultKeyPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid configuration.git.keyPattern: %w", err)
+	}
+
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return "", nil
+	}
+	for _, group := range match[1:] {
+		if group != "" {
+			return strings.ToUpper(group), nil
+		}
+	}
+	return strings.ToUpper(match[0]), nil
+}
+
+// SelectRecentIssue lets user pick one of issues recently updated,
+// which user is assigned to or reported
+func SelectRecentIssue(jiraClient *jira.Client, prompter prompt.Prompter) (string, error) {
+	recent, err := Search(jiraClient, recentIssuesJQL, []string{"summary"}, recentIssuesLimit)
+	if err != nil {
+		return "", fmt.Errorf("cannot find recent issues: %w", err)
+	}
+	if len(recent) == 0 {
+		return "", errors.New("issue key is required, no recent issues were found")
+	}
+
+	options := make([]string, 0, len(recent))
+	for _, issue := range recent {
+		summary := ""
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+		}
+		options = append(options, issue.Key+" "+summary)
+	}
+
+	choice, err := prompter.Select("Select issue", options)
+	if err != nil {
+		return "", err
+	}
+	return strings.Fields(choice)[0], nil
+}
@@ -0,0 +1,207 @@
+package issues
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyFromBranch(t *testing.T) {
+	tests := []struct {
+		branch  string
+		pattern string
+		want    string
+		wantErr string
+	}{
+		{branch: "feature/proj-123/login", want: "PROJ-123"},
+		{branch: "task/proj-1-fix-login-page", want: "PROJ-1"},
+		{branch: "PROJ-42", want: "PROJ-42"},
+		{branch: "jane/ab_2-7", want: "AB_2-7"},
+		{branch: "main"},
+		{branch: "bump-deps"},
+		{branch: "release/2-0-1"},
+		{branch: "dependabot/npm_and_yarn/lodash-4.17.21"},
+		{branch: "release-2024.1"},
+		{branch: "hotfix-2-typo"},
+		{branch: "renovate/node-18.x"},
+		{branch: "hotfix/v2-proj-5", pattern: `proj-\d+`, want: "PROJ-5"},
+		{branch: "x/PROJ-5", pattern: `^[a-z]+/([A-Z]+-\d+)`, want: "PROJ-5"},
+		{branch: "main", pattern: `(`, wantErr: "invalid configuration.git.keyPattern: error parsing regexp: missing closing ): `(`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			got, err := KeyFromBranch(tt.branch, tt.pattern)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsKey(t *testing.T) {
+	assert.True(t, IsKey("PROJ-12"))
+	assert.True(t, IsKey("proj-1"))
+	assert.False(t, IsKey("In Progress"))
+	assert.False(t, IsKey("PROJ-0"))
+	assert.False(t, IsKey("QA"))
+}
+
+func TestKeyResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		config      string
+		branch      string
+		branchErr   error
+		neverPrompt bool
+		recent      bool
+		// found is key of branch issue looked up with status
+		found   string
+		status  int
+		want    string
+		wantErr string
+	}{
+		{
+			name: "key given as argument",
+			args: []string{"proj-7"},
+			want: "PROJ-7",
+		},
+		{
+			name:   "key from branch",
+			branch: "feature/proj-123/login",
+			found:  "PROJ-123",
+			status: 200,
+			want:   "PROJ-123",
+		},
+		{
+			name: "key from branch by configured pattern",
+			config: heredoc.Doc(`
+				configuration:
+				  git:
+				    keyPattern: '^\w+/(\w+-\d+)'
+			`),
+			branch: "jane/ops-9-2fa",
+			found:  "OPS-9",
+			status: 200,
+			want:   "OPS-9",
+		},
+		{
+			name:   "recent issue is picked when issue of branch is not found",
+			branch: "task/proj-9-fix-login",
+			found:  "PROJ-9",
+			status: 404,
+			recent: true,
+			want:   "PROJ-2",
+		},
+		{
+			name:        "issue of branch not found in script",
+			branch:      "task/proj-9-fix-login",
+			found:       "PROJ-9",
+			status:      404,
+			neverPrompt: true,
+			wantErr:     `issue PROJ-9 of branch "task/proj-9-fix-login" not found, give key as argument or set configuration.git.keyPattern`,
+		},
+		{
+			name:   "recent issue is picked when branch has no key",
+			branch: "main",
+			recent: true,
+			want:   "PROJ-2",
+		},
+		{
+			name:      "recent issue is picked outside of git repository",
+			branchErr: errors.New("repository does not exist"),
+			recent:    true,
+			want:      "PROJ-2",
+		},
+		{
+			name:        "branch without key in script",
+			branch:      "main",
+			neverPrompt: true,
+			wantErr:     `cannot find issue key in branch "main", give it as argument or set configuration.git.keyPattern`,
+		},
+		{
+			name:        "no branch in script",
+			branchErr:   errors.New("repository does not exist"),
+			neverPrompt: true,
+			wantErr:     "issue key is required, give it as argument or run command on branch with issue key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.found != "" {
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/"+tt.found, url.Values{"fields": []string{"summary"}}),
+					httpmock.StatusStringResponse(tt.status, `{"key": "`+tt.found+`"}`),
+				)
+			}
+			if tt.recent {
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{"assignee = currentUser() OR reporter = currentUser() ORDER BY updated DESC"},
+					}),
+					httpmock.JSONResponse(map[string]interface{}{
+						"issues": []map[string]interface{}{
+							{"key": "PROJ-1", "fields": map[string]interface{}{"summary": "Login fails"}},
+							{"key": "PROJ-2", "fields": map[string]interface{}{"summary": "Bump dependencies"}},
+						},
+						"isLast": true,
+					}),
+				)
+			}
+
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CurrentBranchFunc = func() (string, error) {
+				return tt.branch, tt.branchErr
+			}
+
+			ios := &iostreams.IOStream{}
+			ios.SetNeverPrompt(tt.neverPrompt)
+
+			r := &KeyResolver{
+				Config: func() (config.Config, error) {
+					return config.NewFromString(tt.config), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				Prompter: &prompt.PrompterMock{
+					SelectFunc: func(s string, options []string) (string, error) {
+						assert.Equal(t, "Select issue", s)
+						assert.Equal(t, []string{"PROJ-1 Login fails", "PROJ-2 Bump dependencies"}, options)
+						return options[1], nil
+					},
+				},
+				IOStream: ios,
+			}
+
+			got, err := r.Resolve(tt.args)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+type linkTypesResponse struct {
+	IssueLinkTypes []jira.IssueLinkType `json:"issueLinkTypes"`
+}
+
+// GetLinkTypes returns issue link types configured in jira
+func GetLinkTypes(jiraClient *jira.Client) ([]jira.IssueLinkType, error) {
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet, "rest/api/3/issueLinkType", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &linkTypesResponse{}
+	resp, err := jiraClient.Do(req, result)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+
+	return result.IssueLinkTypes, nil
+}
+
+// FindLinkType finds link type by its name or by its inward
+// or outward description, e.g. "Blocks" or "is blocked by"
+func FindLinkType(linkTypes []jira.IssueLinkType, name string) (*jira.IssueLinkType, error) {
+	names := make([]string, 0, len(linkTypes))
+	for i, t := range linkTypes {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.Inward, name) || strings.EqualFold(t.Outward, name) {
+			return &linkTypes[i], nil
+		}
+		names = append(names, t.Name)
+	}
+
+	return nil, fmt.Errorf("link type %q not found, available link types are: %s", name, strings.Join(names, ", "))
+}
+
+// CreateLink links two issues, so that the link reads
+// "<fromKey> <outward description> <toKey>", e.g. "PROJ-1 blocks PROJ-2"
+func CreateLink(jiraClient *jira.Client, linkType *jira.IssueLinkType, fromKey, toKey string) error {
+	type issueRef struct {
+		Key string `json:"key"`
+	}
+	// jira shows outward description on the issue sent as inwardIssue
+	body := struct {
+		Type         map[string]string `json:"type"`
+		InwardIssue  issueRef          `json:"inwardIssue"`
+		OutwardIssue issueRef          `json:"outwardIssue"`
+	}{
+		Type:         map[string]string{"name": linkType.Name},
+		InwardIssue:  issueRef{Key: fromKey},
+		OutwardIssue: issueRef{Key: toKey},
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost, "rest/api/3/issueLink", body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+
+	return nil
+}
+
+// DescribeLink describes link as seen from the issue it belongs to,
+// e.g. "blocks PROJ-2" or "is blocked by PROJ-3"
+func DescribeLink(link *jira.IssueLink) string {
+	if link.OutwardIssue != nil {
+		return link.Type.Outward + " " + link.OutwardIssue.Key
+	}
+	if link.InwardIssue != nil {
+		return link.Type.Inward + " " + link.InwardIssue.Key
+	}
+
+	return link.Type.Name
+}
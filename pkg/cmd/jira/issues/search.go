@@ -0,0 +1,86 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// searchPageSize is a max number of issues jira returns per search page
+const searchPageSize = 100
+
+type searchPage struct {
+	Issues        []json.RawMessage `json:"issues"`
+	NextPageToken string            `json:"nextPageToken"`
+	IsLast        bool              `json:"isLast"`
+}
+
+// Search returns issues matching jql with given fields only.
+// At most limit issues are returned, limit <= 0 means all matching issues.
+func Search(jiraClient *jira.Client, jql string, fields []string, limit int) ([]jira.Issue, error) {
+	found, err := SearchRaw(jiraClient, jql, fields, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]jira.Issue, len(found))
+	for i, raw := range found {
+		if err = json.Unmarshal(raw, &result[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// SearchRaw is like Search, but it returns issues as they are returned by jira api
+func SearchRaw(jiraClient *jira.Client, jql string, fields []string, limit int) ([]json.RawMessage, error) {
+	var result []json.RawMessage
+	token := ""
+	for {
+		pageSize := searchPageSize
+		if limit > 0 && limit-len(result) < pageSize {
+			pageSize = limit - len(result)
+		}
+
+		values := url.Values{}
+		values.Set("jql", jql)
+		values.Set("maxResults", strconv.Itoa(pageSize))
+		if len(fields) > 0 {
+			values.Set("fields", strings.Join(fields, ","))
+		}
+		if token != "" {
+			values.Set("nextPageToken", token)
+		}
+
+		req, err := jiraClient.NewRequest(context.Background(),
+			http.MethodGet, "rest/api/3/search/jql?"+values.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &searchPage{}
+		resp, err := jiraClient.Do(req, page)
+		if err != nil {
+			return nil, jira.NewJiraError(resp, err)
+		}
+
+		result = append(result, page.Issues...)
+		token = page.NextPageToken
+		if page.IsLast || token == "" || len(page.Issues) == 0 || (limit > 0 && len(result) >= limit) {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// QuoteJQL quotes value to be safely used in jql query
+func QuoteJQL(value string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(value) + `"`
+}
@@ -0,0 +1,122 @@
+package list
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+)
+
+const (
+	// noAssignee lists unassigned issues
+	noAssignee = "none"
+
+	currentSprint = "current"
+	futureSprint  = "future"
+
+	defaultOrder = "ORDER BY updated DESC"
+)
+
+var (
+	// relativeTime is a jql relative time, e.g. 7d or 2w
+	relativeTime = regexp.MustCompile(`^[0-9]+[wdhm]$`)
+	orderBy      = regexp.MustCompile(`(?i)\border\s+by\b`)
+)
+
+// buildJQL joins filters and raw jql given by user into one query.
+// Jira doesn't allow unrestricted queries, so issues assigned to current user
+// are listed, when no project, assignee, sprint, label or jql is given.
+func buildJQL(jiraClient *jira.Client, ops *ListOptions) (string, error) {
+	var clauses []string
+	restricted := ops.JQL != "" || ops.Project != "" || ops.Sprint != "" || len(ops.Labels) > 0
+
+	if ops.Project != "" {
+		clauses = append(clauses, "project = "+issues.QuoteJQL(ops.Project))
+	}
+
+	switch assignee := ops.Assignee; {
+	case assignee == "" && !restricted:
+		clauses = append(clauses, "assignee = currentUser()")
+	case assignee == "":
+	case assignee == users.Me:
+		clauses = append(clauses, "assignee = currentUser()")
+	case strings.EqualFold(assignee, noAssignee):
+		clauses = append(clauses, "assignee is EMPTY")
+	default:
+		u, err := users.FindUser(jiraClient, assignee)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, "assignee = "+issues.QuoteJQL(u.AccountID))
+	}
+
+	switch {
+	case len(ops.Statuses) > 0:
+		clauses = append(clauses, "status in "+quoteList(ops.Statuses))
+	case ops.JQL == "":
+		clauses = append(clauses, "statusCategory != Done")
+	}
+
+	if len(ops.Types) > 0 {
+		clauses = append(clauses, "issuetype in "+quoteList(ops.Types))
+	}
+
+	// every label must be present
+	for _, label := range ops.Labels {
+		clauses = append(clauses, "labels = "+issues.QuoteJQL(label))
+	}
+
+	switch sprint := ops.Sprint; {
+	case sprint == "":
+	case strings.EqualFold(sprint, currentSprint):
+		clauses = append(clauses, "sprint in openSprints()")
+	case strings.EqualFold(sprint, futureSprint):
+		clauses = append(clauses, "sprint in futureSprints()")
+	default:
+		clauses = append(clauses, "sprint = "+issues.QuoteJQL(sprint))
+	}
+
+	if ops.UpdatedSince != "" {
+		since, err := updatedSince(ops.UpdatedSince)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, "updated >= "+since)
+	}
+
+	order := defaultOrder
+	if ops.JQL != "" {
+		query := ops.JQL
+		if loc := orderBy.FindStringIndex(query); loc != nil {
+			query, order = query[:loc[0]], query[loc[0]:]
+		}
+		if query = strings.TrimSpace(query); query != "" {
+			clauses = append(clauses, "("+query+")")
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(clauses, " AND ") + " " + order), nil
+}
+
+// updatedSince returns jql value of relative time like 7d, or of date like 2022-11-01
+func updatedSince(value string) (string, error) {
+	if relativeTime.MatchString(value) {
+		return "-" + value, nil
+	}
+	if _, err := time.Parse("2006-01-02", value); err == nil {
+		return issues.QuoteJQL(value), nil
+	}
+	return "", fmt.Errorf("invalid --updated-since %q, use time like 7d, 2w, 12h or date like 2022-11-01", value)
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, issues.QuoteJQL(v))
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
@@ -0,0 +1,255 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/export"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// defaultLimit is a number of issues listed when --limit is not given
+const defaultLimit = 30
+
+// listFields are jira fields shown in table
+var listFields = []string{"summary", "issuetype", "status", "assignee", "updated"}
+
+type ListOptions struct {
+	JiraClient func() (*jira.Client, error)
+	IOStream   *iostreams.IOStream
+
+	Assignee     string
+	Statuses     []string
+	Project      string
+	Labels       []string
+	Sprint       string
+	Types        []string
+	UpdatedSince string
+	// JQL is joined with other filters, its ORDER BY replaces default order
+	JQL string
+	// Limit is a max number of listed issues, all matching issues are listed when it is 0
+	Limit int
+
+	// Exporter prints issues as json array, or formats them by jq expression or go template
+	Exporter export.Options
+}
+
+// listIssue is an issue with fields shown in table
+type listIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary   string `json:"summary"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Status *struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Assignee *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Updated string `json:"updated"`
+	} `json:"fields"`
+}
+
+func NewListCmd(f *factory.Factory) *cobra.Command {
+	ops := &ListOptions{
+		JiraClient: f.JiraClient,
+		IOStream:   f.IOStream,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "list jira issues",
+		Long: heredoc.Doc(`
+			List jira issues matching filters, most recently updated first.
+
+			Filters are joined into jql query, --jql is added to them as it is. Issues
+			that are done are not listed unless --status or --jql is given. Without project,
+			assignee, sprint, label or jql filter, issues assigned to you are listed.
+
+			Issues are shown as table in terminal, otherwise as tab separated values:
+			key, summary, type, status, assignee and update time.
+		`),
+		Example: heredoc.Doc(`
+			# your open issues
+			$ jh list
+
+			# open issues of current sprint in project
+			$ jh ls --project PROJ --sprint current
+
+			$ jh ls --assignee none --type Bug --label backend --updated-since 7d
+			$ jh ls --status "In Review" --status "In Progress" --assignee jane@example.com
+			$ jh ls --jql 'project = PROJ AND fixVersion = 1.2 ORDER BY priority DESC' --limit 100
+
+			# show every listed issue
+			$ jh ls --sprint current | jh get
+
+			$ jh ls --project PROJ --json=key,summary,status
+			$ jh ls --jq '.[] | select(.fields.assignee == null) | .key'
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ops.Limit < 0 {
+				return fmt.Errorf("invalid --limit %d, it must not be negative", ops.Limit)
+			}
+			return run(ops)
+		},
+	}
+
+	cmd.Flags().StringVarP(&ops.Assignee, "assignee", "a", "", "Filter by assignee: name, email, @me or none")
+	cmd.Flags().StringSliceVarP(&ops.Statuses, "status", "s", nil, "Filter by status (can be repeated)")
+	cmd.Flags().StringVarP(&ops.Project, "project", "p", "", "Filter by project key")
+	cmd.Flags().StringSliceVarP(&ops.Labels, "label", "l", nil, "Filter by label, issues have all given labels (can be repeated)")
+	cmd.Flags().StringVar(&ops.Sprint, "sprint", "", "Filter by sprint: name, current or future")
+	cmd.Flags().StringSliceVarP(&ops.Types, "type", "t", nil, "Filter by issue type (can be repeated)")
+	cmd.Flags().StringVar(&ops.UpdatedSince, "updated-since", "", "Filter issues updated since time like 7d, 2w or date like 2022-11-01")
+	cmd.Flags().StringVarP(&ops.JQL, "jql", "q", "", "Filter by jql query")
+	cmd.Flags().IntVarP(&ops.Limit, "limit", "L", defaultLimit, "Maximum number of issues, 0 lists all")
+	export.AddFlags(cmd, &ops.Exporter, export.TemplateFlag)
+
+	return cmd
+}
+
+func run(ops *ListOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	jql, err := buildJQL(jiraClient, ops)
+	if err != nil {
+		return err
+	}
+
+	fields := listFields
+	if ops.Exporter.Enabled() && len(ops.Exporter.Fields) > 0 {
+		fields = issues.JiraFields(ops.Exporter.Fields)
+	}
+
+	found, err := issues.SearchRaw(jiraClient, jql, fields, ops.Limit)
+	if err != nil {
+		return fmt.Errorf("cannot search issues: %w", err)
+	}
+
+	if ops.Exporter.Enabled() {
+		return exportIssues(ops, found, jiraClient.BaseURL.String())
+	}
+
+	list := make([]*listIssue, 0, len(found))
+	for _, raw := range found {
+		issue := &listIssue{}
+		if err = json.Unmarshal(raw, issue); err != nil {
+			return fmt.Errorf("cannot read issues: %w", err)
+		}
+		list = append(list, issue)
+	}
+
+	if ops.IOStream.IsOutTerminal() {
+		printIssueTable(ops.IOStream, list, jql)
+		return nil
+	}
+
+	for _, issue := range list {
+		fmt.Fprintln(ops.IOStream.Out, strings.Join([]string{
+			issue.Key,
+			tsvValue(issue.Fields.Summary),
+			issue.Fields.IssueType.Name,
+			issue.status(),
+			issue.assignee(),
+			issue.Fields.Updated,
+		}, "\t"))
+	}
+	return nil
+}
+
+func exportIssues(ops *ListOptions, found []json.RawMessage, baseURL string) error {
+	data := make([]interface{}, 0, len(found))
+	for _, raw := range found {
+		if len(ops.Exporter.Fields) == 0 {
+			data = append(data, raw)
+			continue
+		}
+		issue, err := issues.ExportedIssue(raw, baseURL)
+		if err != nil {
+			return fmt.Errorf("cannot read issues: %w", err)
+		}
+		data = append(data, issue)
+	}
+	return ops.Exporter.Write(ops.IOStream, data)
+}
+
+func printIssueTable(ios *iostreams.IOStream, list []*listIssue, jql string) {
+	cs := ios.ColorScheme()
+	if len(list) == 0 {
+		fmt.Fprintf(ios.Out, "No issues match %s\n", jql)
+		return
+	}
+
+	fmt.Fprintf(ios.Out, "%s\n\n", cs.Gray(fmt.Sprintf("Showing %d issues matching %s", len(list), jql)))
+
+	rows := [][]tableCell{{
+		{"KEY", cs.Gray}, {"SUMMARY", cs.Gray}, {"TYPE", cs.Gray},
+		{"STATUS", cs.Gray}, {"ASSIGNEE", cs.Gray}, {"UPDATED", cs.Gray},
+	}}
+	for _, issue := range list {
+		statusStyle := cs.Blue
+		if issue.Fields.Status != nil {
+			statusStyle = issues.StatusColor(cs, issue.Fields.Status.StatusCategory.Key)
+		}
+		assignee := tableCell{issue.assignee(), nil}
+		if assignee.text == "" {
+			assignee = tableCell{"Unassigned", cs.Gray}
+		}
+		rows = append(rows, []tableCell{
+			{issue.Key, cs.Bold},
+			{tsvValue(issue.Fields.Summary), nil},
+			{issue.Fields.IssueType.Name, nil},
+			{issue.status(), statusStyle},
+			assignee,
+			{formatDate(issue.Fields.Updated), cs.Gray},
+		})
+	}
+
+	// summary is shortened to fit terminal
+	printTable(ios.Out, ios.TerminalWidth(), 1, rows)
+}
+
+func (i *listIssue) status() string {
+	if i.Fields.Status == nil {
+		return ""
+	}
+	return i.Fields.Status.Name
+}
+
+func (i *listIssue) assignee() string {
+	if i.Fields.Assignee == nil {
+		return ""
+	}
+	return i.Fields.Assignee.DisplayName
+}
+
+func formatDate(value string) string {
+	t, err := time.Parse(issues.JiraTimeLayout, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("2006-01-02")
+}
+
+// tsvValue replaces tabs and new lines, so that value stays in its column and row
+func tsvValue(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(value)
+}
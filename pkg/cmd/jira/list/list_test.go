@@ -0,0 +1,215 @@
+package list
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+const issuesJSON = `{"isLast": true, "issues": [
+	{"key": "PROJ-1", "fields": {"summary": "Login fails on Safari when password contains unicode",
+		"issuetype": {"name": "Bug"}, "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}},
+		"assignee": {"displayName": "Jane Doe"}, "updated": "2022-11-04T10:00:00.000+0000"}},
+	{"key": "PROJ-2", "fields": {"summary": "Add\tlogout",
+		"issuetype": {"name": "Task"}, "status": {"name": "To Do", "statusCategory": {"key": "new"}},
+		"assignee": null, "updated": "2022-11-01T10:00:00.000+0000"}}
+]}`
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		tty       bool
+		stub      func(r *httpmock.Registry)
+		expectOut string
+		expectErr string
+	}{
+		{
+			name: "should print table fitted to terminal",
+			tty:  true,
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql":        []string{"assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC"},
+						"fields":     []string{"summary,issuetype,status,assignee,updated"},
+						"maxResults": []string{"30"},
+					}),
+					httpmock.StringResponse(issuesJSON),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				Showing 2 issues matching assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC
+
+				KEY     SUMMARY                        TYPE  STATUS       ASSIGNEE    UPDATED
+				PROJ-1  Login fails on Safari when p…  Bug   In Progress  Jane Doe    2022-11-04
+				PROJ-2  Add logout                     Task  To Do        Unassigned  2022-11-01
+			`),
+		},
+		{
+			name: "should print tab separated values and build jql from filters",
+			args: []string{"--project", "PROJ", "--status", "To Do,In Progress", "--type", "Bug",
+				"--label", "web", "--label", "safari", "--sprint", "current", "--updated-since", "2w", "--assignee", "none"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{`project = "PROJ" AND assignee is EMPTY AND status in ("To Do", "In Progress") AND ` +
+							`issuetype in ("Bug") AND labels = "web" AND labels = "safari" AND sprint in openSprints() AND ` +
+							`updated >= -2w ORDER BY updated DESC`},
+					}),
+					httpmock.StringResponse(issuesJSON),
+				)
+			},
+			expectOut: "PROJ-1\tLogin fails on Safari when password contains unicode\tBug\tIn Progress\tJane Doe\t2022-11-04T10:00:00.000+0000\n" +
+				"PROJ-2\tAdd logout\tTask\tTo Do\t\t2022-11-01T10:00:00.000+0000\n",
+		},
+		{
+			name: "should look up assignee and keep order of raw jql",
+			args: []string{"--assignee", "jane", "--jql", "fixVersion = 1.2 order by priority DESC", "--jq", ".[].key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"jane"}}),
+					httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`),
+				)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{`assignee = "42" AND (fixVersion = 1.2) order by priority DESC`},
+					}),
+					httpmock.StringResponse(issuesJSON),
+				)
+			},
+			expectOut: "PROJ-1\nPROJ-2\n",
+		},
+		{
+			name: "should look up assignee by account id",
+			args: []string{"--assignee", "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077", "--jq", ".[].key"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/user", url.Values{"accountId": []string{"557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"}}),
+					httpmock.StringResponse(`{"accountId": "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077", "displayName": "Jane Doe"}`),
+				)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{`assignee = "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077" AND statusCategory != Done ORDER BY updated DESC`},
+					}),
+					httpmock.StringResponse(issuesJSON),
+				)
+			},
+			expectOut: "PROJ-1\nPROJ-2\n",
+		},
+		{
+			name: "should page through search results up to limit",
+			args: []string{"--project", "PROJ", "--limit", "3", "--json=key,url,summary"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"maxResults":    []string{"1"},
+						"nextPageToken": []string{"next"},
+					}),
+					httpmock.StringResponse(`{"isLast": true, "issues": [{"key": "PROJ-3", "fields": {"summary": "Third"}}]}`),
+				)
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"maxResults": []string{"3"},
+						"fields":     []string{"summary"},
+					}),
+					httpmock.StringResponse(`{"nextPageToken": "next", "issues": [
+						{"key": "PROJ-1", "fields": {"summary": "First"}}, {"key": "PROJ-2", "fields": {"summary": "Second"}}]}`),
+				)
+			},
+			expectOut: heredoc.Doc(`
+				[
+				  {
+				    "key": "PROJ-1",
+				    "summary": "First",
+				    "url": "https://jira-url/browse/PROJ-1"
+				  },
+				  {
+				    "key": "PROJ-2",
+				    "summary": "Second",
+				    "url": "https://jira-url/browse/PROJ-2"
+				  },
+				  {
+				    "key": "PROJ-3",
+				    "summary": "Third",
+				    "url": "https://jira-url/browse/PROJ-3"
+				  }
+				]
+			`),
+		},
+		{
+			name: "should report no issues in terminal",
+			args: []string{"--sprint", "Sprint 12", "--status", "Done"},
+			tty:  true,
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/search/jql", url.Values{
+						"jql": []string{`status in ("Done") AND sprint = "Sprint 12" ORDER BY updated DESC`},
+					}),
+					httpmock.StringResponse(`{"isLast": true, "issues": []}`),
+				)
+			},
+			expectOut: "No issues match status in (\"Done\") AND sprint = \"Sprint 12\" ORDER BY updated DESC\n",
+		},
+		{
+			name:      "should reject invalid update time",
+			args:      []string{"--updated-since", "yesterday"},
+			stub:      func(r *httpmock.Registry) {},
+			expectErr: `invalid --updated-since "yesterday"`,
+		},
+		{
+			name: "should return api error",
+			args: []string{"--jql", "project = NOPE"},
+			stub: func(r *httpmock.Registry) {
+				r.Register(
+					httpmock.REST("GET", "rest/api/3/search/jql"),
+					httpmock.StatusStringResponse(400, `{"errorMessages":["The value 'NOPE' does not exist for the field 'project'."]}`),
+				)
+			},
+			expectErr: "cannot search issues",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			tt.stub(reg)
+
+			out := &bytes.Buffer{}
+			ios := &iostreams.IOStream{Out: out}
+			ios.SetOutTerminal(tt.tty)
+			ios.SetTerminalWidth(80)
+			f := &factory.Factory{
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				IOStream: ios,
+			}
+
+			cmd := NewListCmd(f)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			// when
+			_, err := cmd.ExecuteC()
+
+			// then
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+		})
+	}
+}
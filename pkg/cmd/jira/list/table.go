@@ -0,0 +1,77 @@
+package list
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// columnGap separates columns of table
+	columnGap = "  "
+	// minFlexWidth is a width flexible column is never shrunk below
+	minFlexWidth = 10
+)
+
+type tableCell struct {
+	text string
+	// style colors text, it is applied after padding is counted
+	style func(string) string
+}
+
+// printTable writes rows with aligned columns. When table is wider than width,
+// flex column is truncated, so that every row fits into one line of terminal.
+func printTable(out io.Writer, width, flex int, rows [][]tableCell) {
+	if len(rows) == 0 {
+		return
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c.text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	total := len(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	if over := total - width; over > 0 && widths[flex] > minFlexWidth {
+		widths[flex] -= over
+		if widths[flex] < minFlexWidth {
+			widths[flex] = minFlexWidth
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for i, c := range row {
+			text := truncate(c.text, widths[i])
+			padding := widths[i] - utf8.RuneCountInString(text)
+			if c.style != nil && text != "" {
+				text = c.style(text)
+			}
+			line.WriteString(text)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", padding) + columnGap)
+			}
+		}
+		fmt.Fprintln(out, strings.TrimRight(line.String(), " "))
+	}
+}
+
+// truncate shortens text to width, cut text ends with ellipsis
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
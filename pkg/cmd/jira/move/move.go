@@ -0,0 +1,288 @@
+package move
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/issues"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+type MoveOptions struct {
+	JiraIssueKey string
+	// Status is a name of target status or of transition
+	Status     string
+	JiraClient func() (*jira.Client, error)
+	IOStream   *iostreams.IOStream
+	Prompter   prompt.Prompter
+	// KeyResolver finds issue key in current branch, when it is not given
+	KeyResolver *issues.KeyResolver
+
+	// List shows transitions available from current status
+	List bool
+	// Resolution fills in resolution field of transition screen
+	Resolution string
+	// Comment is added along with transition, it is written in markdown
+	Comment string
+}
+
+func NewMoveCmd(f *factory.Factory) *cobra.Command {
+	ops := &MoveOptions{
+		JiraClient:  f.JiraClient,
+		IOStream:    f.IOStream,
+		Prompter:    f.Prompter,
+		KeyResolver: issues.NewKeyResolver(f),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "move [<jira-key>] [<status>]",
+		Aliases: []string{"mv"},
+		Short:   "move jira issue to another status",
+		Long: heredoc.Doc(`
+			Move jira issue to another status by one of transitions available from its current status.
+
+			Status is matched by name of target status or of transition, case is ignored.
+			When it matches several transitions or none exactly, transitions containing
+			it are offered to pick from. Without status, transition is picked from all available.
+
+			Fields required by transition screen, e.g. resolution, are asked for unless
+			they are given by flags.
+
+			Without key, issue key is taken from current git branch.
+		`),
+		Example: heredoc.Doc(`
+			$ jh move PROJ-1 "In Progress"
+			$ jh mv PROJ-1 review
+
+			# issue of current branch
+			$ jh move done --resolution Fixed --comment "Released in **1.2**"
+
+			# transitions available from current status
+			$ jh move PROJ-1 --list
+		`),
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) == 2:
+				ops.JiraIssueKey, ops.Status = strings.ToUpper(args[0]), args[1]
+			case len(args) == 1 && (ops.List || issues.IsKey(args[0])):
+				ops.JiraIssueKey = strings.ToUpper(args[0])
+			case len(args) == 1:
+				ops.Status = args[0]
+			}
+
+			if ops.List && ops.Status != "" {
+				return fmt.Errorf("unexpected argument %q, status is not used with --list", ops.Status)
+			}
+			if !ops.List && ops.Status == "" && !ops.IOStream.CanPrompt() {
+				return errors.New("status is required when not running interactively, run with --list to see available ones")
+			}
+
+			if ops.JiraIssueKey == "" {
+				key, err := ops.KeyResolver.Resolve(nil)
+				if err != nil {
+					return err
+				}
+				ops.JiraIssueKey = key
+			}
+			return run(ops)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&ops.List, "list", "l", false, "List transitions available from current status")
+	cmd.Flags().StringVarP(&ops.Resolution, "resolution", "r", "", "Set resolution, when transition screen has it")
+	cmd.Flags().StringVarP(&ops.Comment, "comment", "m", "", "Add comment written in markdown, @name mentions a user")
+	cmd.MarkFlagsMutuallyExclusive("list", "resolution")
+	cmd.MarkFlagsMutuallyExclusive("list", "comment")
+
+	return cmd
+}
+
+func run(ops *MoveOptions) error {
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := getTransitions(jiraClient, ops.JiraIssueKey)
+	if err != nil {
+		return err
+	}
+
+	current := issue.Fields.Status.Name
+	if ops.List {
+		printTransitions(ops.IOStream, issue)
+		return nil
+	}
+	if len(issue.Transitions) == 0 {
+		return fmt.Errorf("%s cannot be moved from %s, no transitions are available", ops.JiraIssueKey, current)
+	}
+
+	t, err := pickTransition(ops, issue)
+	if err != nil || t == nil {
+		return err
+	}
+
+	fields, err := screenValues(ops, jiraClient, t)
+	if err != nil {
+		return err
+	}
+
+	var comment *adf.Node
+	if ops.Comment != "" {
+		comment = adf.FromMarkdown(ops.Comment, users.NewMentionResolver(jiraClient))
+	} else if f := t.Fields[commentField]; f != nil && f.Required {
+		if comment, err = askComment(ops); err != nil {
+			return err
+		}
+	}
+
+	// comment is added by transition only when it is on transition screen
+	var update map[string]interface{}
+	if _, ok := t.Fields[commentField]; ok && comment != nil {
+		update = map[string]interface{}{
+			commentField: []interface{}{map[string]interface{}{"add": map[string]interface{}{"body": comment}}},
+		}
+	}
+
+	if err = doTransition(jiraClient, ops.JiraIssueKey, t, fields, update); err != nil {
+		return err
+	}
+	fmt.Fprintf(ops.IOStream.Out, "moved %s: %s → %s\n", ops.JiraIssueKey, current, t.To.Name)
+
+	if comment != nil && update == nil {
+		if _, err = issues.AddComment(jiraClient, ops.JiraIssueKey, comment); err != nil {
+			return fmt.Errorf("cannot add comment to %s: %w", ops.JiraIssueKey, err)
+		}
+		fmt.Fprintf(ops.IOStream.Out, "commented %s\n", ops.JiraIssueKey)
+	}
+	return nil
+}
+
+// pickTransition finds transition to given status, or lets user pick one.
+// Nil is returned when issue is already in given status.
+func pickTransition(ops *MoveOptions, issue *issueTransitions) (*transition, error) {
+	if ops.Status == "" {
+		return selectTransition(ops.Prompter, "Move "+ops.JiraIssueKey+" to", issue.Transitions)
+	}
+
+	current := issue.Fields.Status.Name
+	matched := matchTransitions(issue.Transitions, ops.Status)
+	switch {
+	case len(matched) == 0 && strings.EqualFold(current, ops.Status):
+		fmt.Fprintf(ops.IOStream.Out, "%s is already in %s\n", ops.JiraIssueKey, current)
+		return nil, nil
+	case len(matched) == 0:
+		return nil, fmt.Errorf("%s cannot be moved from %s to %q, available transitions: %s",
+			ops.JiraIssueKey, current, ops.Status, strings.Join(transitionLabels(issue.Transitions), ", "))
+	case len(matched) == 1:
+		return matched[0], nil
+	case !ops.IOStream.CanPrompt():
+		return nil, fmt.Errorf("%q matches several transitions: %s", ops.Status, strings.Join(transitionLabels(matched), ", "))
+	}
+	return selectTransition(ops.Prompter, fmt.Sprintf("%q matches several transitions, move %s by", ops.Status, ops.JiraIssueKey), matched)
+}
+
+// screenValues returns values of transition screen fields, resolution is taken from flag,
+// other required fields are asked for
+func screenValues(ops *MoveOptions, jiraClient *jira.Client, t *transition) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if ops.Resolution != "" {
+		f, ok := t.Fields[resolutionField]
+		if !ok {
+			return nil, fmt.Errorf("resolution cannot be set, it is not on screen of transition %s", t.label())
+		}
+		v, err := fieldValue(jiraClient, f, ops.Resolution)
+		if err != nil {
+			return nil, err
+		}
+		values[resolutionField] = v
+	}
+
+	for _, id := range t.requiredFields() {
+		if _, ok := values[id]; ok || id == commentField {
+			continue
+		}
+
+		f := t.Fields[id]
+		if !f.settable() {
+			return nil, fmt.Errorf("transition %s requires %s, it cannot be set from jh", t.label(), f.Name)
+		}
+		if !ops.IOStream.CanPrompt() {
+			if id == resolutionField {
+				return nil, fmt.Errorf("transition %s requires resolution, set it with --resolution", t.label())
+			}
+			return nil, fmt.Errorf("transition %s requires %s, it can be set in interactive mode only", t.label(), f.Name)
+		}
+
+		answer, err := askFieldValue(ops.Prompter, f)
+		if err != nil {
+			return nil, err
+		}
+		v, err := fieldValue(jiraClient, f, answer)
+		if err != nil {
+			return nil, err
+		}
+		values[id] = v
+	}
+	return values, nil
+}
+
+func askComment(ops *MoveOptions) (*adf.Node, error) {
+	if !ops.IOStream.CanPrompt() {
+		return nil, errors.New("transition requires comment, set it with --comment")
+	}
+	text, err := ops.Prompter.Editor("Comment", "")
+	if err != nil {
+		return nil, err
+	}
+	jiraClient, err := ops.JiraClient()
+	if err != nil {
+		return nil, err
+	}
+	return adf.FromMarkdown(adf.StripComments(text), users.NewMentionResolver(jiraClient)), nil
+}
+
+func printTransitions(ios *iostreams.IOStream, issue *issueTransitions) {
+	out, cs := ios.Out, ios.ColorScheme()
+	status := issue.Fields.Status
+	fmt.Fprintf(out, "%s %s %s\n", cs.Bold(issue.Key), cs.Gray("is in"),
+		issues.StatusColor(cs, status.StatusCategory.Key)(status.Name))
+
+	if len(issue.Transitions) == 0 {
+		fmt.Fprintln(out, cs.Gray("No transitions available"))
+		return
+	}
+
+	fmt.Fprintln(out, "\nTransitions")
+	for _, t := range issue.Transitions {
+		printTransition(out, cs, t)
+	}
+}
+
+func printTransition(out io.Writer, cs *iostreams.ColorScheme, t *transition) {
+	to := issues.StatusColor(cs, t.To.StatusCategory.Key)(t.To.Name)
+	line := "  " + to
+	if !strings.EqualFold(t.Name, t.To.Name) {
+		line = fmt.Sprintf("  %s → %s", t.Name, to)
+	}
+
+	var required []string
+	for _, id := range t.requiredFields() {
+		required = append(required, strings.ToLower(t.Fields[id].Name))
+	}
+	if len(required) > 0 {
+		line += " " + cs.Gray("(requires "+strings.Join(required, ", ")+")")
+	}
+	fmt.Fprintln(out, line)
+}
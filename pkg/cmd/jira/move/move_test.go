@@ -0,0 +1,328 @@
+package move
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/MakeNowJust/heredoc"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/tests/httpmock"
+	"github.com/stirboy/jh/pkg/config"
+	"github.com/stirboy/jh/pkg/factory"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+const transitionsJSON = `{"key": "PROJ-1", "fields": {"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}},
+	"transitions": [
+		{"id": "11", "name": "Review", "to": {"name": "Code Review", "statusCategory": {"key": "indeterminate"}}},
+		{"id": "21", "name": "Done", "to": {"name": "Done", "statusCategory": {"key": "done"}}, "fields": {
+			"resolution": {"name": "Resolution", "required": true, "schema": {"type": "resolution"},
+				"allowedValues": [{"id": "1", "name": "Fixed"}, {"id": "2", "name": "Won't Do"}]},
+			"comment": {"name": "Comment", "required": false, "schema": {"type": "comments-page"}}}},
+		{"id": "31", "name": "Reject", "to": {"name": "Done", "statusCategory": {"key": "done"}}},
+		{"id": "41", "name": "Stop Progress", "to": {"name": "To Do", "statusCategory": {"key": "new"}}}
+	]}`
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		tty         bool
+		selects     map[string]string
+		expectBody  string
+		expectOut   string
+		expectErr   string
+		noPost      bool
+		commentBody string
+		// fromBranch looks up issue key of current branch
+		fromBranch bool
+		// transitions replace transitionsJSON, inputs answer input prompts
+		transitions string
+		inputs      map[string]string
+	}{
+		{
+			name:       "should move issue by target status ignoring case",
+			args:       []string{"proj-1", "code review"},
+			expectBody: `{"transition": {"id": "11"}}`,
+			expectOut:  "moved PROJ-1: In Progress → Code Review\n",
+		},
+		{
+			name:       "should move issue of current branch by part of status",
+			args:       []string{"to d"},
+			fromBranch: true,
+			expectBody: `{"transition": {"id": "41"}}`,
+			expectOut:  "moved PROJ-1: In Progress → To Do\n",
+		},
+		{
+			name: "should pick ambiguous transition and ask for resolution",
+			args: []string{"PROJ-1", "done", "--comment", "Released in **1.2**"},
+			tty:  true,
+			selects: map[string]string{
+				`"done" matches several transitions, move PROJ-1 by`: "Done",
+				"Resolution": "Won't Do",
+			},
+			expectBody: `{"transition": {"id": "21"}, "fields": {"resolution": {"id": "2"}}, "update": {"comment": [{"add": {"body":
+				{"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [
+					{"type": "text", "text": "Released in "}, {"type": "text", "text": "1.2", "marks": [{"type": "strong"}]}]}]}}}]}}`,
+			expectOut: "moved PROJ-1: In Progress → Done\n",
+		},
+		{
+			name:       "should set resolution by flag",
+			args:       []string{"PROJ-1", "Done", "--resolution", "fixed"},
+			tty:        true,
+			selects:    map[string]string{`"Done" matches several transitions, move PROJ-1 by`: "Done"},
+			expectBody: `{"transition": {"id": "21"}, "fields": {"resolution": {"id": "1"}}}`,
+			expectOut:  "moved PROJ-1: In Progress → Done\n",
+		},
+		{
+			name:        "should add comment when it is not on transition screen",
+			args:        []string{"PROJ-1", "Stop Progress", "-m", "Blocked"},
+			expectBody:  `{"transition": {"id": "41"}}`,
+			commentBody: `{"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Blocked"}]}]}}`,
+			expectOut:   "moved PROJ-1: In Progress → To Do\ncommented PROJ-1\n",
+		},
+		{
+			name:       "should pick transition without status",
+			args:       []string{"PROJ-1"},
+			tty:        true,
+			selects:    map[string]string{"Move PROJ-1 to": "Reject → Done"},
+			expectBody: `{"transition": {"id": "31"}}`,
+			expectOut:  "moved PROJ-1: In Progress → Done\n",
+		},
+		{
+			name:   "should list transitions",
+			args:   []string{"PROJ-1", "--list"},
+			noPost: true,
+			expectOut: heredoc.Doc(`
+				PROJ-1 is in In Progress
+
+				Transitions
+				  Review → Code Review
+				  Done (requires resolution)
+				  Reject → Done
+				  Stop Progress → To Do
+			`),
+		},
+		{
+			name: "should send textarea field of transition screen as document",
+			args: []string{"PROJ-1", "Blocked"},
+			tty:  true,
+			transitions: `{"key": "PROJ-1", "fields": {"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}},
+				"transitions": [{"id": "51", "name": "Block", "to": {"name": "Blocked", "statusCategory": {"key": "indeterminate"}}, "fields": {
+					"customfield_10": {"name": "Reason", "required": true, "schema": {"type": "string",
+						"custom": "com.atlassian.jira.plugin.system.customfieldtypes:textarea"}}}}]}`,
+			inputs: map[string]string{"Reason": "Waits for **API**"},
+			expectBody: `{"transition": {"id": "51"}, "fields": {"customfield_10": {"type": "doc", "version": 1, "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Waits for "}, {"type": "text", "text": "API", "marks": [{"type": "strong"}]}]}]}}}`,
+			expectOut: "moved PROJ-1: In Progress → Blocked\n",
+		},
+		{
+			name:      "should not move issue already in status",
+			args:      []string{"PROJ-1", "in progress"},
+			noPost:    true,
+			expectOut: "PROJ-1 is already in In Progress\n",
+		},
+		{
+			name:      "should fail on ambiguous status when not interactive",
+			args:      []string{"PROJ-1", "done"},
+			noPost:    true,
+			expectErr: `"done" matches several transitions: Done, Reject → Done`,
+		},
+		{
+			name:      "should fail on unknown status",
+			args:      []string{"PROJ-1", "deploy"},
+			noPost:    true,
+			expectErr: `PROJ-1 cannot be moved from In Progress to "deploy", available transitions: Review → Code Review, Done, Reject → Done, Stop Progress → To Do`,
+		},
+		{
+			name:      "should fail on invalid resolution",
+			args:      []string{"PROJ-1", "Done", "--resolution", "Duplicate"},
+			tty:       true,
+			selects:   map[string]string{`"Done" matches several transitions, move PROJ-1 by`: "Done"},
+			noPost:    true,
+			expectErr: `invalid resolution "Duplicate", allowed values: Fixed, Won't Do`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.fromBranch {
+				reg.Register(
+					httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"fields": []string{"summary"}}),
+					httpmock.StringResponse(`{"key": "PROJ-1"}`),
+				)
+			}
+			transitions := transitionsJSON
+			if tt.transitions != "" {
+				transitions = tt.transitions
+			}
+			reg.Register(
+				httpmock.QueryMatcher("GET", "rest/api/3/issue/PROJ-1", url.Values{"expand": []string{"transitions.fields"}}),
+				httpmock.StringResponse(transitions),
+			)
+
+			var body, commentBody json.RawMessage
+			if !tt.noPost {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue/PROJ-1/transitions"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&body)
+						return httpmock.StatusStringResponse(204, "")(req)
+					},
+				)
+			}
+			if tt.commentBody != "" {
+				reg.Register(
+					httpmock.REST("POST", "rest/api/3/issue/PROJ-1/comment"),
+					func(req *http.Request) (*http.Response, error) {
+						_ = json.NewDecoder(req.Body).Decode(&commentBody)
+						return httpmock.StatusStringResponse(201, "{}")(req)
+					},
+				)
+			}
+
+			out := &bytes.Buffer{}
+			ios := &iostreams.IOStream{Out: out}
+			ios.SetNeverPrompt(!tt.tty)
+
+			gitClient := gitclient.NewGitClientMock()
+			gitClient.CurrentBranchFunc = func() (string, error) {
+				return "feature/proj-1/login", nil
+			}
+			f := &factory.Factory{
+				Config: func() (config.Config, error) {
+					return config.NewBlankConfig(), nil
+				},
+				JiraClient: func() (*jira.Client, error) {
+					return jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+				},
+				GitClient: func() (gitclient.GitClient, error) {
+					return gitClient, nil
+				},
+				// prompter panics on any prompt which is not stubbed
+				Prompter: &prompt.PrompterMock{
+					SelectFunc: func(message string, options []string) (string, error) {
+						answer, ok := tt.selects[message]
+						if !ok {
+							t.Fatalf("unexpected prompt %q", message)
+						}
+						assert.Contains(t, options, answer)
+						return answer, nil
+					},
+					InputFunc: func(message, def string, askOpts ...survey.AskOpt) (string, error) {
+						answer, ok := tt.inputs[message]
+						if !ok {
+							t.Fatalf("unexpected prompt %q", message)
+						}
+						return answer, nil
+					},
+				},
+				IOStream: ios,
+			}
+
+			cmd := NewMoveCmd(f)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			// when
+			_, err := cmd.ExecuteC()
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+			if tt.expectBody != "" {
+				assert.JSONEq(t, tt.expectBody, string(body))
+			}
+			if tt.commentBody != "" {
+				assert.JSONEq(t, tt.commentBody, string(commentBody))
+			}
+		})
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		value     string
+		stub      func(r *httpmock.Registry)
+		expect    string
+		expectErr string
+	}{
+		{
+			name:   "should pick allowed value by name",
+			field:  `{"name": "Resolution", "schema": {"type": "resolution"}, "allowedValues": [{"id": "1", "name": "Fixed"}]}`,
+			value:  "fixed",
+			expect: `{"id": "1"}`,
+		},
+		{
+			name:   "should trim items of array",
+			field:  `{"name": "Labels", "schema": {"type": "array", "items": "string"}}`,
+			value:  "backend, ui ,db",
+			expect: `["backend", "ui", "db"]`,
+		},
+		{
+			name:  "should find user by name",
+			field: `{"name": "Reviewer", "schema": {"type": "user"}}`,
+			value: "jane",
+			stub: func(r *httpmock.Registry) {
+				r.Register(httpmock.QueryMatcher("GET", "rest/api/3/user/search", url.Values{"query": []string{"jane"}}),
+					httpmock.StringResponse(`[{"accountId": "42", "displayName": "Jane Doe"}]`))
+			},
+			expect: `{"accountId": "42"}`,
+		},
+		{
+			name:      "should fail on option without allowed values",
+			field:     `{"name": "Severity", "schema": {"type": "option"}}`,
+			value:     "high",
+			expectErr: "Severity of type option cannot be set from jh",
+		},
+		{
+			name:      "should fail on version",
+			field:     `{"name": "Fix Version", "schema": {"type": "array", "items": "version"}}`,
+			value:     "1.2",
+			expectErr: "Fix Version of type array cannot be set from jh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			reg := &httpmock.Registry{}
+			defer reg.Verify(t)
+			if tt.stub != nil {
+				tt.stub(reg)
+			}
+			jiraClient, _ := jira.NewClient("https://jira-url", &http.Client{Transport: reg})
+			f := &screenField{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.field), f))
+
+			// when
+			v, err := fieldValue(jiraClient, f, tt.value)
+
+			// then
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			data, _ := json.Marshal(v)
+			assert.JSONEq(t, tt.expect, string(data))
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package move
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
+	"github.com/stirboy/jh/pkg/cmd/jira/users"
+)
+
+const (
+	resolutionField = "resolution"
+	commentField    = "comment"
+)
+
+// issueTransitions is an issue with its status and transitions
+// available from it, fields of transition screens are expanded
+type issueTransitions struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status transitionStatus `json:"status"`
+	} `json:"fields"`
+	Transitions []*transition `json:"transitions"`
+}
+
+type transition struct {
+	ID   string           `json:"id"`
+	Name string           `json:"name"`
+	To   transitionStatus `json:"to"`
+	// Fields are fields of transition screen keyed by field id
+	Fields map[string]*screenField `json:"fields"`
+}
+
+type transitionStatus struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type screenField struct {
+	Name            string `json:"name"`
+	Required        bool   `json:"required"`
+	HasDefaultValue bool   `json:"hasDefaultValue"`
+	Schema          struct {
+		Type string `json:"type"`
+		// Items is type of array items
+		Items  string `json:"items"`
+		System string `json:"system"`
+		Custom string `json:"custom"`
+	} `json:"schema"`
+	AllowedValues []allowedValue `json:"allowedValues"`
+}
+
+type allowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (v allowedValue) label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	if v.Value != "" {
+		return v.Value
+	}
+	return v.ID
+}
+
+// label describes transition, its name is omitted when it is the same as target status
+func (t *transition) label() string {
+	if strings.EqualFold(t.Name, t.To.Name) {
+		return t.To.Name
+	}
+	return fmt.Sprintf("%s → %s", t.Name, t.To.Name)
+}
+
+// requiredFields returns ids of fields user has to fill in, sorted by id
+func (t *transition) requiredFields() []string {
+	var ids []string
+	for id, f := range t.Fields {
+		if f.Required && !f.HasDefaultValue {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func getTransitions(jiraClient *jira.Client, key string) (*issueTransitions, error) {
+	values := url.Values{}
+	values.Set("fields", "status")
+	values.Set("expand", "transitions.fields")
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodGet,
+		"rest/api/3/issue/"+url.PathEscape(key)+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &issueTransitions{}
+	resp, err := jiraClient.Do(req, issue)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get transitions of %s: %w", key, jira.NewJiraError(resp, err))
+	}
+	return issue, nil
+}
+
+func doTransition(jiraClient *jira.Client, key string, t *transition, fields, update map[string]interface{}) error {
+	body := map[string]interface{}{
+		"transition": map[string]string{"id": t.ID},
+	}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	if len(update) > 0 {
+		body["update"] = update
+	}
+
+	req, err := jiraClient.NewRequest(context.Background(), http.MethodPost,
+		"rest/api/3/issue/"+url.PathEscape(key)+"/transitions", body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := jiraClient.Do(req, nil)
+	if err != nil {
+		return fmt.Errorf("cannot move %s to %s: %w", key, t.To.Name, jira.NewJiraError(resp, err))
+	}
+	return nil
+}
+
+// matchTransitions returns transitions whose target status or name equals target,
+// ignoring case. When there are none, transitions containing target are returned.
+func matchTransitions(transitions []*transition, target string) []*transition {
+	var exact, partial []*transition
+	lower := strings.ToLower(target)
+	for _, t := range transitions {
+		switch {
+		case strings.EqualFold(t.To.Name, target) || strings.EqualFold(t.Name, target):
+			exact = append(exact, t)
+		case strings.Contains(strings.ToLower(t.To.Name), lower) || strings.Contains(strings.ToLower(t.Name), lower):
+			partial = append(partial, t)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+func transitionLabels(transitions []*transition) []string {
+	labels := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		labels = append(labels, t.label())
+	}
+	return labels
+}
+
+// selectTransition lets user pick one of transitions by its label
+func selectTransition(prompter prompt.Prompter, message string, transitions []*transition) (*transition, error) {
+	choice, err := prompter.Select(message, transitionLabels(transitions))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transitions {
+		if t.label() == choice {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown transition %q", choice)
+}
+
+// isRichText reports whether field value is expected in atlassian document format
+func (f *screenField) isRichText() bool {
+	return f.Schema.System == "description" ||
+		f.Schema.System == "environment" ||
+		f.Schema.Custom == "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+}
+
+// settable reports whether value of field can be built from text given or picked by user
+func (f *screenField) settable() bool {
+	if len(f.AllowedValues) > 0 {
+		return true
+	}
+	switch f.Schema.Type {
+	case "string", "number", "date", "datetime", "user":
+		return true
+	case "array":
+		return f.Schema.Items == "string"
+	}
+	return false
+}
+
+// fieldValue converts value given or picked by user into value of field in transition request
+func fieldValue(jiraClient *jira.Client, f *screenField, value string) (interface{}, error) {
+	if len(f.AllowedValues) > 0 {
+		for _, v := range f.AllowedValues {
+			if strings.EqualFold(v.label(), value) || v.ID == value {
+				if f.Schema.Type == "array" {
+					return []map[string]string{{"id": v.ID}}, nil
+				}
+				return map[string]string{"id": v.ID}, nil
+			}
+		}
+		labels := make([]string, 0, len(f.AllowedValues))
+		for _, v := range f.AllowedValues {
+			labels = append(labels, v.label())
+		}
+		return nil, fmt.Errorf("invalid %s %q, allowed values: %s", strings.ToLower(f.Name), value, strings.Join(labels, ", "))
+	}
+
+	if !f.settable() {
+		return nil, fmt.Errorf("%s of type %s cannot be set from jh", f.Name, f.Schema.Type)
+	}
+
+	if f.isRichText() {
+		return adf.FromMarkdown(value, users.NewMentionResolver(jiraClient)), nil
+	}
+
+	switch f.Schema.Type {
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q, it must be a number", strings.ToLower(f.Name), value)
+		}
+		return n, nil
+	case "user":
+		u, err := users.FindUser(jiraClient, value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find %s: %w", strings.ToLower(f.Name), err)
+		}
+		return map[string]string{"accountId": u.AccountID}, nil
+	case "array":
+		items := strings.Split(value, ",")
+		for i := range items {
+			items[i] = strings.TrimSpace(items[i])
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// askFieldValue asks user for value of screen field
+func askFieldValue(prompter prompt.Prompter, f *screenField) (string, error) {
+	if len(f.AllowedValues) == 0 {
+		return prompter.Input(f.Name, "", survey.WithValidator(survey.Required))
+	}
+
+	labels := make([]string, 0, len(f.AllowedValues))
+	for _, v := range f.AllowedValues {
+		labels = append(labels, v.label())
+	}
+	return prompter.Select(f.Name, labels)
+}
@@ -13,9 +13,11 @@ const (
 type Prompter interface {
 	Select(string, []string) (string, error)
 	SelectWithHelp(string, string, []string) (string, error)
+	MultiSelect(string, []string) ([]string, error)
 	Input(string, string, ...survey.AskOpt) (string, error)
 	InputWithHelp(string, string, string, ...survey.AskOpt) (string, error)
 	Confirm(string) (bool, error)
+	Editor(string, string) (string, error)
 }
 
 func NewPrompter() Prompter {
@@ -47,6 +49,16 @@ func (p *surveyPrompter) SelectWithHelp(message string, help string, options []s
 	return
 }
 
+func (p *surveyPrompter) MultiSelect(message string, options []string) (result []string, err error) {
+	prompt := &survey.MultiSelect{
+		Message: message,
+		Options: options,
+	}
+
+	err = askSurvey(prompt, &result, survey.WithValidator(survey.Required))
+	return
+}
+
 func (p *surveyPrompter) Input(message string, defaultValue string, ops ...survey.AskOpt) (result string, err error) {
 	prompt := &survey.Input{
 		Message: message,
@@ -81,6 +93,20 @@ func (p *surveyPrompter) Confirm(message string) (bool, error) {
 	return false, nil
 }
 
+// Editor opens $EDITOR with defaultValue as initial content of markdown file
+func (p *surveyPrompter) Editor(message, defaultValue string) (result string, err error) {
+	prompt := &survey.Editor{
+		Message:       message,
+		Default:       defaultValue,
+		HideDefault:   true,
+		AppendDefault: true,
+		FileName:      "*.md",
+	}
+
+	err = askSurvey(prompt, &result)
+	return
+}
+
 func askSurvey(p survey.Prompt, r interface{}, ops ...survey.AskOpt) error {
 	ops = append(ops,
 		survey.WithIcons(func(icons *survey.IconSet) {
@@ -21,12 +21,18 @@ var _ Prompter = &PrompterMock{}
 //			ConfirmFunc: func(s string) (bool, error) {
 //				panic("mock out the Confirm method")
 //			},
+//			EditorFunc: func(s1 string, s2 string) (string, error) {
+//				panic("mock out the Editor method")
+//			},
 //			InputFunc: func(s1 string, s2 string, askOpts ...survey.AskOpt) (string, error) {
 //				panic("mock out the Input method")
 //			},
 //			InputWithHelpFunc: func(s1 string, s2 string, s3 string, askOpts ...survey.AskOpt) (string, error) {
 //				panic("mock out the InputWithHelp method")
 //			},
+//			MultiSelectFunc: func(s string, strings []string) ([]string, error) {
+//				panic("mock out the MultiSelect method")
+//			},
 //			SelectFunc: func(s string, strings []string) (string, error) {
 //				panic("mock out the Select method")
 //			},
@@ -43,12 +49,18 @@ type PrompterMock struct {
 	// ConfirmFunc mocks the Confirm method.
 	ConfirmFunc func(s string) (bool, error)
 
+	// EditorFunc mocks the Editor method.
+	EditorFunc func(s1 string, s2 string) (string, error)
+
 	// InputFunc mocks the Input method.
 	InputFunc func(s1 string, s2 string, askOpts ...survey.AskOpt) (string, error)
 
 	// InputWithHelpFunc mocks the InputWithHelp method.
 	InputWithHelpFunc func(s1 string, s2 string, s3 string, askOpts ...survey.AskOpt) (string, error)
 
+	// MultiSelectFunc mocks the MultiSelect method.
+	MultiSelectFunc func(s string, strings []string) ([]string, error)
+
 	// SelectFunc mocks the Select method.
 	SelectFunc func(s string, strings []string) (string, error)
 
@@ -62,6 +74,13 @@ type PrompterMock struct {
 			// S is the s argument value.
 			S string
 		}
+		// Editor holds details about calls to the Editor method.
+		Editor []struct {
+			// S1 is the s1 argument value.
+			S1 string
+			// S2 is the s2 argument value.
+			S2 string
+		}
 		// Input holds details about calls to the Input method.
 		Input []struct {
 			// S1 is the s1 argument value.
@@ -82,6 +101,13 @@ type PrompterMock struct {
 			// AskOpts is the askOpts argument value.
 			AskOpts []survey.AskOpt
 		}
+		// MultiSelect holds details about calls to the MultiSelect method.
+		MultiSelect []struct {
+			// S is the s argument value.
+			S string
+			// Strings is the strings argument value.
+			Strings []string
+		}
 		// Select holds details about calls to the Select method.
 		Select []struct {
 			// S is the s argument value.
@@ -100,8 +126,10 @@ type PrompterMock struct {
 		}
 	}
 	lockConfirm        sync.RWMutex
+	lockEditor         sync.RWMutex
 	lockInput          sync.RWMutex
 	lockInputWithHelp  sync.RWMutex
+	lockMultiSelect    sync.RWMutex
 	lockSelect         sync.RWMutex
 	lockSelectWithHelp sync.RWMutex
 }
@@ -138,6 +166,42 @@ func (mock *PrompterMock) ConfirmCalls() []struct {
 	return calls
 }
 
+// Editor calls EditorFunc.
+func (mock *PrompterMock) Editor(s1 string, s2 string) (string, error) {
+	if mock.EditorFunc == nil {
+		panic("PrompterMock.EditorFunc: method is nil but Prompter.Editor was just called")
+	}
+	callInfo := struct {
+		S1 string
+		S2 string
+	}{
+		S1: s1,
+		S2: s2,
+	}
+	mock.lockEditor.Lock()
+	mock.calls.Editor = append(mock.calls.Editor, callInfo)
+	mock.lockEditor.Unlock()
+	return mock.EditorFunc(s1, s2)
+}
+
+// EditorCalls gets all the calls that were made to Editor.
+// Check the length with:
+//
+//	len(mockedPrompter.EditorCalls())
+func (mock *PrompterMock) EditorCalls() []struct {
+	S1 string
+	S2 string
+} {
+	var calls []struct {
+		S1 string
+		S2 string
+	}
+	mock.lockEditor.RLock()
+	calls = mock.calls.Editor
+	mock.lockEditor.RUnlock()
+	return calls
+}
+
 // Input calls InputFunc.
 func (mock *PrompterMock) Input(s1 string, s2 string, askOpts ...survey.AskOpt) (string, error) {
 	if mock.InputFunc == nil {
@@ -222,6 +286,42 @@ func (mock *PrompterMock) InputWithHelpCalls() []struct {
 	return calls
 }
 
+// MultiSelect calls MultiSelectFunc.
+func (mock *PrompterMock) MultiSelect(s string, strings []string) ([]string, error) {
+	if mock.MultiSelectFunc == nil {
+		panic("PrompterMock.MultiSelectFunc: method is nil but Prompter.MultiSelect was just called")
+	}
+	callInfo := struct {
+		S       string
+		Strings []string
+	}{
+		S:       s,
+		Strings: strings,
+	}
+	mock.lockMultiSelect.Lock()
+	mock.calls.MultiSelect = append(mock.calls.MultiSelect, callInfo)
+	mock.lockMultiSelect.Unlock()
+	return mock.MultiSelectFunc(s, strings)
+}
+
+// MultiSelectCalls gets all the calls that were made to MultiSelect.
+// Check the length with:
+//
+//	len(mockedPrompter.MultiSelectCalls())
+func (mock *PrompterMock) MultiSelectCalls() []struct {
+	S       string
+	Strings []string
+} {
+	var calls []struct {
+		S       string
+		Strings []string
+	}
+	mock.lockMultiSelect.RLock()
+	calls = mock.calls.MultiSelect
+	mock.lockMultiSelect.RUnlock()
+	return calls
+}
+
 // Select calls SelectFunc.
 func (mock *PrompterMock) Select(s string, strings []string) (string, error) {
 	if mock.SelectFunc == nil {
@@ -2,7 +2,9 @@ package prompt
 
 import (
 	"errors"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func EmptyStringValidator(value string) error {
@@ -11,3 +13,19 @@ func EmptyStringValidator(value string) error {
 	}
 	return nil
 }
+
+// NumberValidator is a survey validator which accepts integer and decimal numbers
+func NumberValidator(ans interface{}) error {
+	if _, err := strconv.ParseFloat(strings.TrimSpace(ans.(string)), 64); err != nil {
+		return errors.New("value must be a number")
+	}
+	return nil
+}
+
+// DateValidator is a survey validator which accepts dates in YYYY-MM-DD format
+func DateValidator(ans interface{}) error {
+	if _, err := time.Parse("2006-01-02", strings.TrimSpace(ans.(string))); err != nil {
+		return errors.New("value must be a date in YYYY-MM-DD format")
+	}
+	return nil
+}
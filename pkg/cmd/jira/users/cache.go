@@ -0,0 +1,102 @@
+package users
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/config"
+)
+
+const (
+	cacheFileName = "users.json"
+	// cacheTTL is how long looked up user is trusted, users are rarely renamed
+	cacheTTL = 7 * 24 * time.Hour
+)
+
+// Cache remembers users found by search queries, so that repeated lookups
+// don't call jira. Users are cached per scope, e.g. jira site and project,
+// cache is kept in a file.
+type Cache struct {
+	path    string
+	entries map[string]map[string]*cachedUser
+	// now is replaced in tests
+	now func() time.Time
+}
+
+type cachedUser struct {
+	AccountID    string    `json:"accountId"`
+	DisplayName  string    `json:"displayName"`
+	EmailAddress string    `json:"emailAddress,omitempty"`
+	SavedAt      time.Time `json:"savedAt"`
+}
+
+// NewCache returns cache kept in file at path, file is read on first lookup
+func NewCache(path string) *Cache {
+	return &Cache{path: path, now: time.Now}
+}
+
+// DefaultCachePath returns path of users cache in jh cache directory
+func DefaultCachePath() string {
+	return filepath.Join(config.CacheDir(), cacheFileName)
+}
+
+// Get returns user found earlier by query in scope, queries are case insensitive
+func (c *Cache) Get(scope, query string) (*jira.User, bool) {
+	c.load()
+	u, ok := c.entries[scope][strings.ToLower(query)]
+	if !ok || c.now().Sub(u.SavedAt) > cacheTTL {
+		return nil, false
+	}
+	return &jira.User{AccountID: u.AccountID, DisplayName: u.DisplayName, EmailAddress: u.EmailAddress}, true
+}
+
+// Set remembers user found by query in scope
+func (c *Cache) Set(scope, query string, u *jira.User) error {
+	c.load()
+	if c.entries[scope] == nil {
+		c.entries[scope] = map[string]*cachedUser{}
+	}
+	c.entries[scope][strings.ToLower(query)] = &cachedUser{
+		AccountID:    u.AccountID,
+		DisplayName:  u.DisplayName,
+		EmailAddress: u.EmailAddress,
+		SavedAt:      c.now(),
+	}
+	return c.write()
+}
+
+// Delete forgets user found by query, e.g. when cached user cannot be used anymore
+func (c *Cache) Delete(scope, query string) error {
+	c.load()
+	if _, ok := c.entries[scope][strings.ToLower(query)]; !ok {
+		return nil
+	}
+	delete(c.entries[scope], strings.ToLower(query))
+	return c.write()
+}
+
+// load reads cache file, missing or broken file means empty cache
+func (c *Cache) load() {
+	if c.entries != nil {
+		return
+	}
+	c.entries = map[string]map[string]*cachedUser{}
+	if data, err := os.ReadFile(c.path); err == nil {
+		_ = json.Unmarshal(data, &c.entries)
+	}
+}
+
+func (c *Cache) write() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0771); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
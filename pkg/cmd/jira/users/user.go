@@ -2,10 +2,22 @@ package users
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/adf"
+	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
 )
 
+// Me is a special user query which resolves to the authenticated user
+const Me = "@me"
+
 type CurrentUserResult struct {
 	User *jira.User
 	Err  error
@@ -35,3 +47,222 @@ func GetCurrentUser(jiraClient *jira.Client) (*jira.User, *jira.Response, error)
 	u, resp, err := jiraClient.User.GetCurrentUser(context.Background())
 	return u, resp, err
 }
+
+// SearchUsers returns users whose display name or email matches query
+func SearchUsers(jiraClient *jira.Client, query string) ([]jira.User, error) {
+	values := url.Values{}
+	values.Set("query", query)
+
+	req, err := jiraClient.NewRequest(context.Background(),
+		http.MethodGet, "rest/api/3/user/search?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []jira.User{}
+	resp, err := jiraClient.Do(req, &users)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+
+	return users, nil
+}
+
+// SearchAssignableUsers returns users who can be assigned to the issue and whose
+// display name or email matches query, all assignable users when query is empty
+func SearchAssignableUsers(jiraClient *jira.Client, issueKey, query string, limit int) ([]jira.User, error) {
+	values := url.Values{}
+	values.Set("issueKey", issueKey)
+	values.Set("query", query)
+	values.Set("maxResults", strconv.Itoa(limit))
+
+	req, err := jiraClient.NewRequest(context.Background(),
+		http.MethodGet, "rest/api/3/user/assignable/search?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []jira.User{}
+	resp, err := jiraClient.Do(req, &users)
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+
+	return users, nil
+}
+
+// ExactMatch returns user whose account id, email or display name equals query
+func ExactMatch(found []jira.User, query string) *jira.User {
+	for i := range found {
+		u := &found[i]
+		if u.AccountID == query ||
+			strings.EqualFold(u.EmailAddress, query) ||
+			strings.EqualFold(u.DisplayName, query) {
+			return u
+		}
+	}
+	return nil
+}
+
+// accountIDPattern matches jira cloud account ids, e.g. 5b10ac8d82e05b22cc7d4ef5
+// or 557058:f58131cb-b67d-43c7-b30d-6b58d40bd077, user search doesn't find them
+var accountIDPattern = regexp.MustCompile(`^(?:[0-9a-f]{24}|[0-9]+:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// GetUser returns user with given account id
+func GetUser(jiraClient *jira.Client, accountID string) (*jira.User, error) {
+	values := url.Values{}
+	values.Set("accountId", accountID)
+
+	req, err := jiraClient.NewRequest(context.Background(),
+		http.MethodGet, "rest/api/3/user?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &jira.User{}
+	resp, err := jiraClient.Do(req, user)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no user found for %q", accountID)
+	}
+	if err != nil {
+		return nil, jira.NewJiraError(resp, err)
+	}
+
+	return user, nil
+}
+
+// FindUser resolves query to exactly one user.
+// "@me" resolves to the authenticated user, account id is looked up
+// directly, anything else is matched against email and display name.
+func FindUser(jiraClient *jira.Client, query string) (*jira.User, error) {
+	if query == Me {
+		u, _, err := GetCurrentUser(jiraClient)
+		return u, err
+	}
+	if accountIDPattern.MatchString(query) {
+		return GetUser(jiraClient, query)
+	}
+
+	found, err := SearchUsers(jiraClient, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no user found for %q", query)
+	case 1:
+		return &found[0], nil
+	}
+
+	// prefer exact match when search is ambiguous
+	if u := ExactMatch(found, query); u != nil {
+		return u, nil
+	}
+
+	names := make([]string, 0, len(found))
+	for _, u := range found {
+		names = append(names, u.DisplayName)
+	}
+	return nil, fmt.Errorf("%q matches several users: %s", query, strings.Join(names, ", "))
+}
+
+// PickUser asks for a search query and lets user pick one of matching users
+func PickUser(jiraClient *jira.Client, prompter prompt.Prompter, message string) (*jira.User, error) {
+	query, err := prompter.Input(message+" (search by name or email)", "", survey.WithValidator(survey.Required))
+	if err != nil {
+		return nil, err
+	}
+
+	return pickFound(jiraClient, prompter, message, query)
+}
+
+// PickUsers asks for users one by one until the answer is empty, at least one user is required
+func PickUsers(jiraClient *jira.Client, prompter prompt.Prompter, message string) ([]*jira.User, error) {
+	first, err := PickUser(jiraClient, prompter, message)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := []*jira.User{first}
+	for {
+		query, err := prompter.Input(message+" (search by name or email, empty to finish)", "")
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(query) == "" {
+			return picked, nil
+		}
+
+		u, err := pickFound(jiraClient, prompter, message, query)
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, u)
+	}
+}
+
+// pickFound lets user pick one of users matching query
+func pickFound(jiraClient *jira.Client, prompter prompt.Prompter, message, query string) (*jira.User, error) {
+	found, err := SearchUsers(jiraClient, query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no user found for %q", query)
+	case 1:
+		return &found[0], nil
+	}
+
+	options := make([]string, 0, len(found))
+	for _, u := range found {
+		options = append(options, Label(&u))
+	}
+
+	choice, err := prompter.Select(message, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range found {
+		if Label(&found[i]) == choice {
+			return &found[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no user found for %q", choice)
+}
+
+// Label describes user by name and email, it is used in pickers
+func Label(u *jira.User) string {
+	if u.EmailAddress != "" {
+		return fmt.Sprintf("%s <%s>", u.DisplayName, u.EmailAddress)
+	}
+	return u.DisplayName
+}
+
+// NewMentionResolver returns resolver of @mentions which looks users up
+// by name or email. Lookups are cached, so every name is searched only once.
+func NewMentionResolver(jiraClient *jira.Client) adf.MentionResolver {
+	accountIDs := make(map[string]string)
+	return func(name string) (string, bool) {
+		if id, ok := accountIDs[name]; ok {
+			return id, id != ""
+		}
+
+		query := name
+		if "@"+name == Me {
+			query = Me
+		}
+
+		var id string
+		if u, err := FindUser(jiraClient, query); err == nil {
+			id = u.AccountID
+		}
+		accountIDs[name] = id
+
+		return id, id != ""
+	}
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/stirboy/jh/internal/yamlmap"
 )
 
-const JhConfigDir = "JH_CONFIG_DIR"
+const (
+	JhConfigDir = "JH_CONFIG_DIR"
+	// JhCacheDir overrides directory of cached jira lookups, e.g. of users
+	JhCacheDir = "JH_CACHE_DIR"
+)
 
 var (
 	c         *cfg
@@ -23,6 +27,7 @@ type Config interface {
 	AuthToken() (string, error)
 	Get(string) (string, error)
 	GetNested([]string) (string, error)
+	Decode([]string, interface{}) error
 	Set(string, string)
 	SetNested([]string, string)
 	Write() error
@@ -40,6 +45,8 @@ func NewConfig() (Config, error) {
 type cfg struct {
 	entries *yamlmap.Map
 	mu      sync.RWMutex
+	// path of the file to write to, general config file is used when empty
+	path string
 }
 
 func (c *cfg) AuthToken() (string, error) {
@@ -75,6 +82,21 @@ func (c *cfg) GetNested(keys []string) (string, error) {
 	return m.Value, nil
 }
 
+// Decode decodes nested yaml value, e.g. a list or a map, into v
+func (c *cfg) Decode(keys []string, v interface{}) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m := c.entries
+	for _, key := range keys {
+		var err error
+		m, err = m.Get(key)
+		if err != nil {
+			return KeyNotFoundError{key}
+		}
+	}
+	return m.Decode(v)
+}
+
 func (c *cfg) Set(key, val string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -102,7 +124,12 @@ func (c *cfg) Write() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	err := writeFile(generalConfigFile(), []byte(c.entries.String()))
+	path := c.path
+	if path == "" {
+		path = generalConfigFile()
+	}
+
+	err := writeFile(path, []byte(c.entries.String()))
 	if err != nil {
 		return err
 	}
@@ -118,6 +145,18 @@ func generalConfigFile() string {
 	return filepath.Join(d, ".config", "jh", "config.yml")
 }
 
+// CacheDir returns directory of cached jira lookups, cache can be removed any time
+func CacheDir() string {
+	if c := os.Getenv(JhCacheDir); c != "" {
+		return c
+	}
+	if d, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(d, "jh")
+	}
+	d, _ := os.UserHomeDir()
+	return filepath.Join(d, ".cache", "jh")
+}
+
 func writeFile(filename string, data []byte) error {
 	err := os.MkdirAll(filepath.Dir(filename), 0771)
 	if err != nil {
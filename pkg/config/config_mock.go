@@ -20,6 +20,9 @@ var _ Config = &ConfigMock{}
 //			AuthTokenFunc: func() (string, error) {
 //				panic("mock out the AuthToken method")
 //			},
+//			DecodeFunc: func(strings []string, ifaceVal interface{}) error {
+//				panic("mock out the Decode method")
+//			},
 //			GetFunc: func(s string) (string, error) {
 //				panic("mock out the Get method")
 //			},
@@ -45,6 +48,9 @@ type ConfigMock struct {
 	// AuthTokenFunc mocks the AuthToken method.
 	AuthTokenFunc func() (string, error)
 
+	// DecodeFunc mocks the Decode method.
+	DecodeFunc func(strings []string, ifaceVal interface{}) error
+
 	// GetFunc mocks the Get method.
 	GetFunc func(s string) (string, error)
 
@@ -65,6 +71,13 @@ type ConfigMock struct {
 		// AuthToken holds details about calls to the AuthToken method.
 		AuthToken []struct {
 		}
+		// Decode holds details about calls to the Decode method.
+		Decode []struct {
+			// Strings is the strings argument value.
+			Strings []string
+			// IfaceVal is the ifaceVal argument value.
+			IfaceVal interface{}
+		}
 		// Get holds details about calls to the Get method.
 		Get []struct {
 			// S is the s argument value.
@@ -94,6 +107,7 @@ type ConfigMock struct {
 		}
 	}
 	lockAuthToken sync.RWMutex
+	lockDecode    sync.RWMutex
 	lockGet       sync.RWMutex
 	lockGetNested sync.RWMutex
 	lockSet       sync.RWMutex
@@ -128,6 +142,42 @@ func (mock *ConfigMock) AuthTokenCalls() []struct {
 	return calls
 }
 
+// Decode calls DecodeFunc.
+func (mock *ConfigMock) Decode(strings []string, ifaceVal interface{}) error {
+	if mock.DecodeFunc == nil {
+		panic("ConfigMock.DecodeFunc: method is nil but Config.Decode was just called")
+	}
+	callInfo := struct {
+		Strings  []string
+		IfaceVal interface{}
+	}{
+		Strings:  strings,
+		IfaceVal: ifaceVal,
+	}
+	mock.lockDecode.Lock()
+	mock.calls.Decode = append(mock.calls.Decode, callInfo)
+	mock.lockDecode.Unlock()
+	return mock.DecodeFunc(strings, ifaceVal)
+}
+
+// DecodeCalls gets all the calls that were made to Decode.
+// Check the length with:
+//
+//	len(mockedConfig.DecodeCalls())
+func (mock *ConfigMock) DecodeCalls() []struct {
+	Strings  []string
+	IfaceVal interface{}
+} {
+	var calls []struct {
+		Strings  []string
+		IfaceVal interface{}
+	}
+	mock.lockDecode.RLock()
+	calls = mock.calls.Decode
+	mock.lockDecode.RUnlock()
+	return calls
+}
+
 // Get calls GetFunc.
 func (mock *ConfigMock) Get(s string) (string, error) {
 	if mock.GetFunc == nil {
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/stirboy/jh/internal/yamlmap"
+)
+
+// LocalConfigFileName is a name of repository config file, which is looked up in git root
+const LocalConfigFileName = ".jh.yml"
+
+// LocalConfigFile returns path of repository config file
+func LocalConfigFile(gitRoot string) string {
+	return filepath.Join(gitRoot, LocalConfigFileName)
+}
+
+// NewLocalConfig reads repository config from path.
+// Missing file is treated as empty config, it is created on Write.
+func NewLocalConfig(path string) (Config, error) {
+	m, err := mapFromFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if m == nil {
+		m = yamlmap.MapValue()
+	}
+
+	return &cfg{entries: m, path: path}, nil
+}
+
+// layeredCfg implements Config with repository config layered over user config.
+// Only settings under "configuration" are taken from repository config,
+// so that a repository can't change where credentials are sent to.
+// All changes are written to user config.
+type layeredCfg struct {
+	global Config
+	local  Config
+}
+
+// NewLayeredConfig returns config which prefers local values over global ones
+func NewLayeredConfig(global, local Config) Config {
+	return &layeredCfg{
+		global: global,
+		local:  local,
+	}
+}
+
+func isLocalKey(keys []string) bool {
+	return len(keys) > 0 && keys[0] == "configuration"
+}
+
+func (c *layeredCfg) AuthToken() (string, error) {
+	return c.global.AuthToken()
+}
+
+func (c *layeredCfg) Get(key string) (string, error) {
+	return c.global.Get(key)
+}
+
+func (c *layeredCfg) GetNested(keys []string) (string, error) {
+	if isLocalKey(keys) {
+		if v, err := c.local.GetNested(keys); err == nil && v != "" {
+			return v, nil
+		}
+	}
+	return c.global.GetNested(keys)
+}
+
+// Decode decodes global value first and local value over it,
+// so maps are merged and other values are replaced by local ones
+func (c *layeredCfg) Decode(keys []string, v interface{}) error {
+	var notFound KeyNotFoundError
+
+	globalErr := c.global.Decode(keys, v)
+	if globalErr != nil && !errors.As(globalErr, &notFound) {
+		return globalErr
+	}
+	if !isLocalKey(keys) {
+		return globalErr
+	}
+
+	localErr := c.local.Decode(keys, v)
+	if localErr == nil {
+		return nil
+	}
+	if !errors.As(localErr, &notFound) {
+		return localErr
+	}
+
+	return globalErr
+}
+
+func (c *layeredCfg) Set(key, val string) {
+	c.global.Set(key, val)
+}
+
+func (c *layeredCfg) SetNested(keys []string, val string) {
+	c.global.SetNested(keys, val)
+}
+
+func (c *layeredCfg) Write() error {
+	return c.global.Write()
+}
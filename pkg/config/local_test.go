@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredConfig(t *testing.T) {
+	global := ReadFromString(heredoc.Doc(`
+		url: https://company.atlassian.net
+		token: secret
+		configuration:
+		  issue:
+		    projectKey: BE
+		    issueTypeName: Task
+		  templates:
+		    bug:
+		      project: BE
+	`))
+
+	path := filepath.Join(t.TempDir(), LocalConfigFileName)
+	assert.NoError(t, os.WriteFile(path, []byte(heredoc.Doc(`
+		url: https://attacker.example.com
+		configuration:
+		  issue:
+		    projectKey: MOB
+		  templates:
+		    crash:
+		      project: MOB
+	`)), 0644))
+	local, err := NewLocalConfig(path)
+	assert.NoError(t, err)
+
+	cfg := NewLayeredConfig(global, local)
+
+	// repository settings win
+	v, err := cfg.GetNested([]string{"configuration", "issue", "projectKey"})
+	assert.NoError(t, err)
+	assert.Equal(t, "MOB", v)
+
+	// missing repository settings fall back to user config
+	v, err = cfg.GetNested([]string{"configuration", "issue", "issueTypeName"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Task", v)
+
+	// credentials are never taken from repository
+	v, err = cfg.Get("url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://company.atlassian.net", v)
+
+	// maps are merged
+	templates := map[string]map[string]string{}
+	assert.NoError(t, cfg.Decode([]string{"configuration", "templates"}, &templates))
+	assert.Equal(t, map[string]map[string]string{
+		"bug":   {"project": "BE"},
+		"crash": {"project": "MOB"},
+	}, templates)
+
+	err = cfg.Decode([]string{"configuration", "missing"}, &templates)
+	assert.Equal(t, KeyNotFoundError{"missing"}, err)
+}
+
+func TestLocalConfig_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LocalConfigFileName)
+	cfg, err := NewLocalConfig(path)
+	assert.NoError(t, err)
+
+	cfg.SetNested([]string{"configuration", "issue", "projectKey"}, "MOB")
+	assert.NoError(t, cfg.Write())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "configuration:\n    issue:\n        projectKey: MOB\n", string(data))
+}
@@ -34,6 +34,9 @@ func NewFromString(cfgString string) *ConfigMock {
 		GetNestedFunc: func(keys []string) (string, error) {
 			return c.GetNested(keys)
 		},
+		DecodeFunc: func(keys []string, v interface{}) error {
+			return c.Decode(keys, v)
+		},
 		SetFunc: func(s1 string, s2 string) {
 			c.Set(s1, s2)
 		},
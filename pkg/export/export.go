@@ -0,0 +1,182 @@
+// Package export writes command output as json, filtered by jq expression
+// or formatted by go template, see AddFlags.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+const (
+	// TemplateFlag is a default name of go template flag
+	TemplateFlag = "template"
+
+	// allFields is a value of --json given without fields
+	allFields = "*"
+)
+
+// Options are values of --json, --jq and template flags
+type Options struct {
+	// JSON is set when --json flag is given
+	JSON bool
+	// Fields limits exported fields, all fields are exported when empty
+	Fields   []string
+	JQ       string
+	Template string
+}
+
+// AddFlags adds --json, --jq and go template flags to command, templateFlag
+// names template flag, so that it doesn't clash with flags of the command
+func AddFlags(cmd *cobra.Command, o *Options, templateFlag string) {
+	cmd.Flags().Var(&fieldsValue{o: o}, "json", "Output json with given comma separated fields, all fields when none are given")
+	cmd.Flags().Lookup("json").NoOptDefVal = allFields
+	cmd.Flags().StringVar(&o.JQ, "jq", "", "Filter json output with jq expression")
+	cmd.Flags().StringVar(&o.Template, templateFlag, "", "Format json output with go template")
+	cmd.MarkFlagsMutuallyExclusive("jq", templateFlag)
+}
+
+// Enabled reports whether output should be exported instead of printed for humans
+func (o *Options) Enabled() bool {
+	return o.JSON || o.JQ != "" || o.Template != ""
+}
+
+// Write writes data as json, or formatted by jq expression or go template.
+// Data is encoded to json first, so jq and templates see the same values.
+func (o *Options) Write(ios *iostreams.IOStream, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	value = filterFields(value, o.Fields)
+
+	switch {
+	case o.JQ != "":
+		return filterJQ(ios.Out, o.JQ, value)
+	case o.Template != "":
+		return executeTemplate(ios.Out, ios.ColorScheme(), o.Template, value)
+	}
+
+	enc := json.NewEncoder(ios.Out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+// filterJQ writes every output of jq expression as jq -r prints it:
+// strings as they are, other values in compact json
+func filterJQ(w io.Writer, expr string, value interface{}) error {
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("cannot parse jq expression: %w", err)
+	}
+	code, err := gojq.Compile(q, gojq.WithFunction("keys_unsorted", 0, 0, keysUnsorted))
+	if err != nil {
+		return fmt.Errorf("cannot compile jq expression: %w", err)
+	}
+
+	iter := code.Run(value)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := v.(error); ok {
+			return fmt.Errorf("jq: %w", err)
+		}
+		if s, ok := v.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		out, err := gojq.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(out))
+	}
+}
+
+// keysUnsorted is missing in gojq, because decoded json objects don't keep
+// order of keys. Keys are returned sorted like keys does, so that scripts
+// written for jq still work.
+func keysUnsorted(v interface{}, _ []interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for k := range v {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		keys := make([]interface{}, 0, len(names))
+		for _, k := range names {
+			keys = append(keys, k)
+		}
+		return keys
+	case []interface{}:
+		keys := make([]interface{}, 0, len(v))
+		for i := range v {
+			keys = append(keys, i)
+		}
+		return keys
+	}
+	return fmt.Errorf("%T has no keys", v)
+}
+
+// filterFields keeps given fields of object or of every object in list
+func filterFields(value interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return value
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			out = append(out, filterFields(item, fields))
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			out[f] = v[f]
+		}
+		return out
+	}
+	return value
+}
+
+// fieldsValue is a value of --json flag, which sets Options.JSON when given
+type fieldsValue struct {
+	o *Options
+}
+
+func (v *fieldsValue) String() string {
+	return strings.Join(v.o.Fields, ",")
+}
+
+func (v *fieldsValue) Set(s string) error {
+	v.o.JSON = true
+	if s == allFields {
+		return nil
+	}
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			v.o.Fields = append(v.o.Fields, f)
+		}
+	}
+	return nil
+}
+
+func (v *fieldsValue) Type() string {
+	return "fields"
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+	"github.com/stirboy/jh/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	data := []map[string]interface{}{
+		{"key": "PROJ-1", "summary": "Login fails on Safari", "updated": "2022-11-28T10:00:00.000+0000"},
+		{"key": "PROJ-22", "summary": "Bump", "updated": "2022-11-30T09:30:00.000+0000"},
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		color     bool
+		expectOut string
+		expectErr string
+	}{
+		{
+			name: "all fields",
+			args: []string{"--json"},
+			expectOut: heredoc.Doc(`
+				[
+				  {
+				    "key": "PROJ-1",
+				    "summary": "Login fails on Safari",
+				    "updated": "2022-11-28T10:00:00.000+0000"
+				  },
+				  {
+				    "key": "PROJ-22",
+				    "summary": "Bump",
+				    "updated": "2022-11-30T09:30:00.000+0000"
+				  }
+				]
+			`),
+		},
+		{
+			name:      "selected fields",
+			args:      []string{"--json=key,missing", "--jq", ".[] | tojson"},
+			expectOut: "{\"key\":\"PROJ-1\",\"missing\":null}\n{\"key\":\"PROJ-22\",\"missing\":null}\n",
+		},
+		{
+			name:      "jq",
+			args:      []string{"--jq", `.[] | select(.key | endswith("22")) | .summary`},
+			expectOut: "Bump\n",
+		},
+		{
+			name:      "jq with variables, reduce and formats",
+			args:      []string{"--jq", `reduce .[] as $i (0; . + ($i.summary | length)), (.[] | [.key, .summary] | @tsv), (map(.key) | @csv)`},
+			expectOut: "25\nPROJ-1\tLogin fails on Safari\nPROJ-22\tBump\n\"PROJ-1\",\"PROJ-22\"\n",
+		},
+		{
+			name:      "jq builtins",
+			args:      []string{"--jq", `any(.[]; .key == "PROJ-1"), all(.[]; has("updated")), (.[0] | del(.updated) | keys_unsorted)`},
+			expectOut: "true\ntrue\n[\"key\",\"summary\"]\n",
+		},
+		{
+			name:      "jq runtime error",
+			args:      []string{"--jq", `.[0].key + 1`},
+			expectErr: `jq: cannot add: string ("PROJ-1") and number (1)`,
+		},
+		{
+			name: "template",
+			args: []string{"--template", `{{range .}}{{tablerow .key (truncate 12 .summary) (timeago .updated)}}{{end}}`},
+			expectOut: heredoc.Doc(`
+				PROJ-1   Login fai...  2 days ago
+				PROJ-22  Bump          30 minutes ago
+			`),
+		},
+		{
+			name:      "template with color",
+			args:      []string{"--template", `{{range .}}{{color "green" .key}} {{end}}`},
+			color:     true,
+			expectOut: "\033[32mPROJ-1\033[0m \033[32mPROJ-22\033[0m ",
+		},
+		{
+			name:      "unknown color",
+			args:      []string{"--template", `{{color "pink" "x"}}`},
+			expectErr: `cannot execute template: template: output:1:2: executing "output" at <color "pink" "x">: error calling color: unknown color "pink"`,
+		},
+		{
+			name:      "invalid jq",
+			args:      []string{"--jq", ".["},
+			expectErr: "cannot parse jq expression: unexpected EOF",
+		},
+	}
+
+	now = func() time.Time {
+		return time.Date(2022, 11, 30, 10, 0, 0, 0, time.UTC)
+	}
+	defer func() { now = time.Now }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Options{}
+			cmd := &cobra.Command{RunE: func(*cobra.Command, []string) error { return nil }}
+			AddFlags(cmd, o, TemplateFlag)
+			cmd.SetArgs(tt.args)
+			assert.NoError(t, cmd.Execute())
+			assert.True(t, o.Enabled())
+
+			out := &bytes.Buffer{}
+			ios := &iostreams.IOStream{Out: out}
+			ios.SetColorEnabled(tt.color)
+
+			err := o.Write(ios, data)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out.String())
+		})
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2022, 11, 30, 10, 0, 0, 0, time.UTC)
+	}
+	defer func() { now = time.Now }()
+
+	tests := map[string]string{
+		"2022-11-30T09:59:30.000+0000": "just now",
+		"2022-11-30T09:59:00Z":         "1 minute ago",
+		"2022-11-30T07:00:00.000+0000": "3 hours ago",
+		"2022-11-29T10:00:00.000+0000": "1 day ago",
+		"2022-08-30":                   "3 months ago",
+		"2020-11-30T10:00:00.000+0000": "2 years ago",
+	}
+	for in, want := range tests {
+		got, err := timeAgo(in)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := timeAgo("yesterday")
+	assert.EqualError(t, err, `cannot parse time "yesterday"`)
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/stirboy/jh/pkg/iostreams"
+)
+
+// now is replaced in tests
+var now = time.Now
+
+// timeLayouts are formats of times in jira responses
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+// executeTemplate formats value by go template with helpers:
+//
+//	timeago    time relative to now, e.g. "3 days ago"
+//	truncate   shortens text to given length, e.g. {{truncate 20 .summary}}
+//	color      styles text in terminal, e.g. {{color "green" .status}}
+//	tablerow   writes values as a row of table with aligned columns
+func executeTemplate(out io.Writer, cs *iostreams.ColorScheme, text string, value interface{}) error {
+	funcs := template.FuncMap{
+		"timeago":  timeAgo,
+		"truncate": truncate,
+		"color": func(style string, v interface{}) (string, error) {
+			return colorize(cs, style, fmt.Sprint(v))
+		},
+		"tablerow": tableRow,
+	}
+
+	t, err := template.New("output").Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("cannot parse template: %w", err)
+	}
+
+	// table rows are separated by tabs, columns are aligned once template is done
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if err = t.Execute(w, value); err != nil {
+		return fmt.Errorf("cannot execute template: %w", err)
+	}
+	return w.Flush()
+}
+
+func tableRow(values ...interface{}) string {
+	cells := make([]string, 0, len(values))
+	for _, v := range values {
+		s := ""
+		if v != nil {
+			s = fmt.Sprint(v)
+		}
+		// tabs and new lines would break the table
+		s = strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+		cells = append(cells, s)
+	}
+	return strings.Join(cells, "\t") + "\n"
+}
+
+func truncate(length int, v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	runes := []rune(fmt.Sprint(v))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	if length <= 3 {
+		return string(runes[:length])
+	}
+	return string(runes[:length-3]) + "..."
+}
+
+func colorize(cs *iostreams.ColorScheme, style, text string) (string, error) {
+	styles := map[string]func(string) string{
+		"bold":      cs.Bold,
+		"italic":    cs.Italic,
+		"underline": cs.Underline,
+		"gray":      cs.Gray,
+		"red":       cs.Red,
+		"green":     cs.Green,
+		"yellow":    cs.Yellow,
+		"blue":      cs.Blue,
+		"magenta":   cs.Magenta,
+		"cyan":      cs.Cyan,
+	}
+	f, ok := styles[style]
+	if !ok {
+		return "", fmt.Errorf("unknown color %q", style)
+	}
+	return f(text), nil
+}
+
+func timeAgo(v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	s := fmt.Sprint(v)
+
+	var t time.Time
+	var err error
+	for _, layout := range timeLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot parse time %q", s)
+	}
+
+	d := now().Sub(t)
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return "just now", nil
+	case d < time.Hour:
+		return ago(int(d.Minutes()), "minute"), nil
+	case d < 24*time.Hour:
+		return ago(int(d.Hours()), "hour"), nil
+	case d < 30*24*time.Hour:
+		return ago(int(d.Hours()/24), "day"), nil
+	case d < 365*24*time.Hour:
+		return ago(int(d.Hours()/24/30), "month"), nil
+	}
+	return ago(int(math.Floor(d.Hours()/24/365)), "year"), nil
+}
+
+func ago(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit + " ago"
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
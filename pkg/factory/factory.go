@@ -1,9 +1,11 @@
 package factory
 
 import (
+	"fmt"
 	"os"
 
 	jira "github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/stirboy/jh/pkg/browser"
 	"github.com/stirboy/jh/pkg/cmd/jira/gitclient"
 	"github.com/stirboy/jh/pkg/cmd/jira/prompt"
 	"github.com/stirboy/jh/pkg/config"
@@ -16,33 +18,65 @@ type Factory struct {
 	Prompter   prompt.Prompter
 	GitClient  func() (gitclient.GitClient, error)
 	IOStream   *iostreams.IOStream
+	// Browser opens url in web browser
+	Browser func(string) error
 }
 
 func NewFactory() *Factory {
 	f := &Factory{
-		Config:   configF(),
 		Prompter: prompt.NewPrompter(),
 		IOStream: iostreams.NewIOStream(),
+		Browser:  browser.Open,
 	}
 
-	f.JiraClient = jiraClientF(f) // depends on Config
 	f.GitClient = gitClientF(f)   // depends on IOStream
+	f.Config = configF(f)         // depends on GitClient
+	f.JiraClient = jiraClientF(f) // depends on Config
 
 	return f
 }
 
-func configF() func() (config.Config, error) {
+func configF(f *Factory) func() (config.Config, error) {
 	var cachedConfig config.Config
 	var configError error
 	return func() (config.Config, error) {
 		if cachedConfig != nil || configError != nil {
 			return cachedConfig, configError
 		}
-		cachedConfig, configError := config.NewConfig()
+		cachedConfig, configError = newConfig(f)
 		return cachedConfig, configError
 	}
 }
 
+// newConfig returns user config layered with .jh.yml
+// of current git repository, if there is one
+func newConfig(f *Factory) (config.Config, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	gitClient, err := f.GitClient()
+	if err != nil {
+		return cfg, nil
+	}
+	root, err := gitClient.RootDir()
+	if err != nil {
+		// not a git repository
+		return cfg, nil
+	}
+
+	path := config.LocalConfigFile(root)
+	local, err := config.NewLocalConfig(path)
+	if err != nil {
+		// broken repository config must not stop every command
+		fmt.Fprintf(f.IOStream.ErrOut, "warning: ignoring %s: %v\n", path, err)
+		return cfg, nil
+	}
+
+	return config.NewLayeredConfig(cfg, local), nil
+}
+
 func jiraClientF(f *Factory) func() (*jira.Client, error) {
 	return func() (*jira.Client, error) {
 		cfg, err := f.Config()
@@ -0,0 +1,68 @@
+package iostreams
+
+import "fmt"
+
+// ColorScheme styles text written to terminal with ANSI escape codes,
+// text is returned unchanged when colors are disabled
+type ColorScheme struct {
+	enabled bool
+}
+
+func NewColorScheme(enabled bool) *ColorScheme {
+	return &ColorScheme{enabled: enabled}
+}
+
+func (c *ColorScheme) Enabled() bool {
+	return c.enabled
+}
+
+func (c *ColorScheme) Bold(s string) string {
+	return c.style("1", s)
+}
+
+func (c *ColorScheme) Italic(s string) string {
+	return c.style("3", s)
+}
+
+func (c *ColorScheme) Underline(s string) string {
+	return c.style("4", s)
+}
+
+func (c *ColorScheme) Strike(s string) string {
+	return c.style("9", s)
+}
+
+func (c *ColorScheme) Gray(s string) string {
+	return c.style("90", s)
+}
+
+func (c *ColorScheme) Red(s string) string {
+	return c.style("31", s)
+}
+
+func (c *ColorScheme) Green(s string) string {
+	return c.style("32", s)
+}
+
+func (c *ColorScheme) Yellow(s string) string {
+	return c.style("33", s)
+}
+
+func (c *ColorScheme) Blue(s string) string {
+	return c.style("34", s)
+}
+
+func (c *ColorScheme) Magenta(s string) string {
+	return c.style("35", s)
+}
+
+func (c *ColorScheme) Cyan(s string) string {
+	return c.style("36", s)
+}
+
+func (c *ColorScheme) style(code, s string) string {
+	if !c.enabled || s == "" {
+		return s
+	}
+	return fmt.Sprintf("\033[%sm%s\033[0m", code, s)
+}
@@ -3,14 +3,107 @@ package iostreams
 import (
 	"io"
 	"os"
+
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
 )
 
 type IOStream struct {
+	In  io.Reader
 	Out io.Writer
+	// ErrOut receives warnings, which must not mix with output of commands
+	ErrOut io.Writer
+
+	neverPrompt  bool
+	inPiped      bool
+	outTerminal  bool
+	colorEnabled bool
+	// termWidth is a width of output terminal, it is read on first use when not set
+	termWidth int
 }
 
+// defaultTerminalWidth is used when width of terminal cannot be read
+const defaultTerminalWidth = 80
+
 func NewIOStream() *IOStream {
 	return &IOStream{
-		Out: os.Stdout,
+		In:          os.Stdin,
+		Out:         os.Stdout,
+		ErrOut:      os.Stderr,
+		neverPrompt: !isTerminal(os.Stdin),
+		inPiped:     isPipedOrFile(os.Stdin),
+		outTerminal: isTerminal(os.Stdout),
+		// see https://no-color.org
+		colorEnabled: isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "",
+	}
+}
+
+// CanPrompt reports whether interactive prompts can be shown to the user.
+// It is false when stdin is not a terminal, e.g. in CI jobs or shell pipes.
+func (s *IOStream) CanPrompt() bool {
+	return !s.neverPrompt
+}
+
+func (s *IOStream) SetNeverPrompt(v bool) {
+	s.neverPrompt = v
+}
+
+// IsInPiped reports whether input is a pipe or a redirected file, so that data can be
+// read from it. It is false for terminals and character devices like /dev/null.
+func (s *IOStream) IsInPiped() bool {
+	return s.inPiped
+}
+
+func (s *IOStream) SetInPiped(v bool) {
+	s.inPiped = v
+}
+
+// IsOutTerminal reports whether output is written to a terminal,
+// so that it can be updated in place, e.g. to show progress.
+func (s *IOStream) IsOutTerminal() bool {
+	return s.outTerminal
+}
+
+func (s *IOStream) SetOutTerminal(v bool) {
+	s.outTerminal = v
+}
+
+// TerminalWidth returns number of columns of output terminal
+func (s *IOStream) TerminalWidth() int {
+	if s.termWidth > 0 {
+		return s.termWidth
+	}
+
+	s.termWidth = defaultTerminalWidth
+	if f, ok := s.Out.(*os.File); ok && s.outTerminal {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			s.termWidth = width
+		}
+	}
+	return s.termWidth
+}
+
+func (s *IOStream) SetTerminalWidth(v int) {
+	s.termWidth = v
+}
+
+// ColorScheme returns styles of output, colors are used in terminal only
+func (s *IOStream) ColorScheme() *ColorScheme {
+	return NewColorScheme(s.colorEnabled)
+}
+
+func (s *IOStream) SetColorEnabled(v bool) {
+	s.colorEnabled = v
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+func isPipedOrFile(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Mode().IsRegular()
 }